@@ -50,16 +50,36 @@ func NewLookupStage(from, localField, foreignField, as string) *LookupStage {
 
 // MatchStage math stage cases
 type MatchStage struct {
-	cases primitive.M
+	cases   primitive.M
+	filters []Filter
 }
 
 // GetStage stage getter
 func (m *MatchStage) GetStage() primitive.M {
+	if len(m.filters) == 0 {
+		return primitive.M{
+			MatchAggregateOperator: m.cases,
+		}
+	}
+	match := And(m.filters...).Compile()
+	if len(m.cases) > 0 {
+		match = primitive.D{{Key: AndConditionOperator, Value: primitive.A{m.cases, match}}}
+	}
 	return primitive.M{
-		MatchAggregateOperator: m.cases,
+		MatchAggregateOperator: match,
 	}
 }
 
+// AddFilter add typed filter condition
+func (m *MatchStage) AddFilter(filter Filter) {
+	m.filters = append(m.filters, filter)
+}
+
+// AddExpr add aggregation expression condition
+func (m *MatchStage) AddExpr(expression Expression) {
+	m.AddFilter(Expr(expression))
+}
+
 // AddCondition add aggregation condition
 func (m *MatchStage) AddCondition(key string, value interface{}) {
 	if m.cases == nil {
@@ -115,6 +135,11 @@ func (fs *FacetStage) SetSort(sort primitive.M) {
 	fs.conditions = append(fs.conditions, primitive.M{SortOperator: sort})
 }
 
+// SetSortD set ordered sort, use it for sort by several keys
+func (fs *FacetStage) SetSortD(sort primitive.D) {
+	fs.conditions = append(fs.conditions, primitive.M{SortOperator: sort})
+}
+
 // NewFacetStage create facet stage
 func NewFacetStage() *FacetStage {
 	return &FacetStage{
@@ -138,3 +163,77 @@ func (p *ProjectStage) GetStage() primitive.M {
 func NewProjectStage(project primitive.M) *ProjectStage {
 	return &ProjectStage{projects: project}
 }
+
+// GeoNearStage geo near stage, must be the first stage of pipeline
+type GeoNearStage struct {
+	near          Point
+	distanceField string
+	maxDistance   float64
+	minDistance   float64
+	spherical     bool
+	key           string
+	query         Filter
+}
+
+// GetStage get stage
+func (g *GeoNearStage) GetStage() primitive.M {
+	stage := primitive.M{
+		"near":          g.near,
+		"distanceField": g.distanceField,
+		"spherical":     g.spherical,
+	}
+	if g.maxDistance > 0 {
+		stage["maxDistance"] = g.maxDistance
+	}
+	if g.minDistance > 0 {
+		stage["minDistance"] = g.minDistance
+	}
+	if g.key != "" {
+		stage["key"] = g.key
+	}
+	if g.query != nil {
+		stage["query"] = g.query.Compile()
+	}
+	return primitive.M{
+		GeoNearAggregateOperator: stage,
+	}
+}
+
+// SetMaxDistance set max distance in meters
+func (g *GeoNearStage) SetMaxDistance(distance float64) *GeoNearStage {
+	g.maxDistance = distance
+	return g
+}
+
+// SetMinDistance set min distance in meters
+func (g *GeoNearStage) SetMinDistance(distance float64) *GeoNearStage {
+	g.minDistance = distance
+	return g
+}
+
+// SetSpherical set spherical geometry calculation
+func (g *GeoNearStage) SetSpherical(spherical bool) *GeoNearStage {
+	g.spherical = spherical
+	return g
+}
+
+// SetKey set geospatial index field
+func (g *GeoNearStage) SetKey(key string) *GeoNearStage {
+	g.key = key
+	return g
+}
+
+// SetQuery set filter of documents
+func (g *GeoNearStage) SetQuery(query Filter) *GeoNearStage {
+	g.query = query
+	return g
+}
+
+// NewGeoNearStage create geo near stage, distance is written to distanceField
+func NewGeoNearStage(near Point, distanceField string) *GeoNearStage {
+	return &GeoNearStage{
+		near:          near,
+		distanceField: distanceField,
+		spherical:     true,
+	}
+}
@@ -0,0 +1,119 @@
+package bom
+
+import (
+	"reflect"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestBom_paginationStages(t *testing.T) {
+	b := &Bom{limit: &Limit{Page: 3, Size: 10}}
+	b.WithSort(&Sort{Field: "name", Type: "asc"})
+
+	stages := b.paginationStages(NewPagination(b.limit.Page, b.limit.Size))
+	pipeline, err := stages.Aggregate()
+	if err != nil {
+		t.Fatalf("Aggregate() error = %v", err)
+	}
+	got := pipeline[0][FacetAggregateOperator].(primitive.M)["result"]
+	want := []primitive.M{
+		{SortOperator: primitive.D{{Key: "name", Value: int32(1)}, {Key: "_id", Value: int32(1)}}},
+		{SkipOperator: int32(20)},
+		{LimitOperator: int32(10)},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("paginationStages() result = %v, want %v", got, want)
+	}
+}
+
+func TestDecodeFacet(t *testing.T) {
+	type item struct {
+		Name string `bson:"name"`
+	}
+	facet, _ := bson.Marshal(primitive.D{
+		{Key: "result", Value: primitive.A{primitive.D{{Key: "name", Value: "a"}}, primitive.D{{Key: "name", Value: "b"}}}},
+		{Key: "total", Value: primitive.A{primitive.D{{Key: "_id", Value: nil}, {Key: "count", Value: int32(42)}}}},
+	})
+	empty, _ := bson.Marshal(primitive.D{{Key: "result", Value: primitive.A{}}, {Key: "total", Value: primitive.A{}}})
+
+	tests := []struct {
+		name      string
+		facet     bson.Raw
+		wantItems []item
+		wantCount int64
+	}{
+		{name: "page", facet: facet, wantItems: []item{{Name: "a"}, {Name: "b"}}, wantCount: 42},
+		{name: "empty", facet: empty, wantItems: []item{}},
+		{name: "no document", wantItems: []item{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			items := []item{{Name: "stale"}}
+			count, err := decodeFacet(tt.facet, &items)
+			if err != nil {
+				t.Fatalf("decodeFacet() error = %v", err)
+			}
+			if count != tt.wantCount || !reflect.DeepEqual(items, tt.wantItems) {
+				t.Errorf("decodeFacet() = %v, %v, want %v, %v", items, count, tt.wantItems, tt.wantCount)
+			}
+		})
+	}
+}
+
+func TestMatchStage_GetStage(t *testing.T) {
+	expr := primitive.D{{Key: "$expr", Value: primitive.D{{Key: "$gt", Value: primitive.A{"$spent", "$budget"}}}}}
+	tests := []struct {
+		name  string
+		build func(m *MatchStage)
+		want  primitive.M
+	}{
+		{name: "conditions", build: func(m *MatchStage) { m.AddCondition("status", "active") },
+			want: primitive.M{MatchAggregateOperator: primitive.M{"status": "active"}}},
+		{name: "filter", build: func(m *MatchStage) { m.AddFilter(Gte("age", 18)) },
+			want: primitive.M{MatchAggregateOperator: primitive.D{{Key: "age", Value: primitive.D{{Key: "$gte", Value: 18}}}}}},
+		{name: "expression", build: func(m *MatchStage) { m.AddExpr(ExprGt(ExprField("spent"), ExprField("budget"))) },
+			want: primitive.M{MatchAggregateOperator: expr}},
+		{name: "filters and expression", build: func(m *MatchStage) {
+			m.AddFilter(Eq("status", "active"))
+			m.AddExpr(ExprGt(ExprField("spent"), ExprField("budget")))
+		}, want: primitive.M{MatchAggregateOperator: primitive.D{{Key: AndConditionOperator, Value: primitive.A{
+			primitive.D{{Key: "status", Value: "active"}},
+			expr,
+		}}}}},
+		{name: "conditions, filter and expression", build: func(m *MatchStage) {
+			m.AddCondition("status", "active")
+			m.AddFilter(Gte("age", 18))
+			m.AddExpr(ExprGt(ExprField("spent"), ExprField("budget")))
+		}, want: primitive.M{MatchAggregateOperator: primitive.D{{Key: AndConditionOperator, Value: primitive.A{
+			primitive.M{"status": "active"},
+			primitive.D{{Key: AndConditionOperator, Value: primitive.A{
+				primitive.D{{Key: "age", Value: primitive.D{{Key: "$gte", Value: 18}}}},
+				expr,
+			}}},
+		}}}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := NewMatchStage()
+			tt.build(m)
+			if got := m.GetStage(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetStage() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFacetStage_SetSort(t *testing.T) {
+	facet := NewFacetStage()
+	facet.SetSort(primitive.M{"name": 1})
+	facet.SetSortD(primitive.D{{Key: "name", Value: 1}, {Key: "_id", Value: -1}})
+	want := []primitive.M{
+		{SortOperator: primitive.M{"name": 1}},
+		{SortOperator: primitive.D{{Key: "name", Value: 1}, {Key: "_id", Value: -1}}},
+	}
+	if got := facet.GetStage()[FacetAggregateOperator].(primitive.M)["result"]; !reflect.DeepEqual(got, want) {
+		t.Errorf("GetStage() result = %v, want %v", got, want)
+	}
+}
@@ -6,12 +6,18 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"reflect"
 	"strings"
 	"time"
 
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/readconcern"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
+	"go.mongodb.org/mongo-driver/mongo/writeconcern"
 )
 
 // Define common const
@@ -32,6 +38,9 @@ type (
 		client *mongo.Client
 		model  interface{}
 
+		// parent context of every query, queryTimeout is applied on top of it
+		ctx context.Context
+
 		// configuration fields
 		dbName       string
 		dbCollection string
@@ -43,24 +52,41 @@ type (
 		pipeline         AggregateStages
 
 		// go.mongodb.org/mongo-driver options (with setters)
-		options Options
+		options   Options
+		collation *options.Collation
+
+		// per-query settings of the driver
+		hint           interface{}
+		maxTime        time.Duration
+		comment        string
+		readPreference *readpref.ReadPref
+		readConcern    *readconcern.ReadConcern
+		writeConcern   *writeconcern.WriteConcern
 
 		lastID         string
 		useAggregation bool
 		selectArg      []interface{}
 
 		// query config
-		limit *Limit
-		sort  []*Sort
+		limit             *Limit
+		limitSet          bool // page or size is set by WithLimit or WithSize, Iter is not limited otherwise
+		sort              []*Sort
+		lowercaseSort     bool
+		disableTieBreaker bool
+		countStrategy     CountStrategy
+		concurrentCount   bool
+
+		// keyset pagination position (sort key values or cursor token)
+		after     []interface{}
+		before    []interface{}
+		cursor    string
+		cursorKey []byte
 	}
 
 	// Conditions mongodb conditions structure
 	Conditions struct {
-		whereConditions []map[string]interface{}
-		orConditions    []map[string]interface{}
-		inConditions    []map[string]interface{}
-		notInConditions []map[string]interface{}
-		notConditions   []map[string]interface{}
+		whereConditions []Filter
+		orConditions    []Filter
 	}
 
 	// Options client options
@@ -68,6 +94,7 @@ type (
 		aggregateOptions        []*options.AggregateOptions
 		updateOptions           []*options.UpdateOptions
 		insertOptions           []*options.InsertOneOptions
+		insertManyOptions       []*options.InsertManyOptions
 		findOneOptions          []*options.FindOneOptions
 		findOptions             []*options.FindOptions
 		findOneAndUpdateOptions []*options.FindOneAndUpdateOptions
@@ -85,7 +112,7 @@ type (
 		Size int32
 	}
 
-	// ElemMatch data for projection
+	// ElemMatch data for projection, Val is ElemMatch (equality) or Filter
 	ElemMatch struct {
 		Key string
 		Val interface{}
@@ -99,9 +126,19 @@ type (
 	}
 )
 
-// Mongo source client (go.mongodb.org/mongo-driver)
+// Mongo source client (go.mongodb.org/mongo-driver), read preference, read concern and write concern are applied to collection
 func (b *Bom) Mongo() *mongo.Collection {
-	return b.client.Database(b.dbName).Collection(b.dbCollection)
+	opts := options.Collection()
+	if b.readPreference != nil {
+		opts.SetReadPreference(b.readPreference)
+	}
+	if b.readConcern != nil {
+		opts.SetReadConcern(b.readConcern)
+	}
+	if b.writeConcern != nil {
+		opts.SetWriteConcern(b.writeConcern)
+	}
+	return b.client.Database(b.dbName).Collection(b.dbCollection, opts)
 }
 
 // New init bom object
@@ -127,6 +164,76 @@ func New(options ...Option) (*Bom, error) {
 	return b, nil
 }
 
+// Clone create copy of bom with all conditions, sort, pipeline and options,
+// the copy can be modified without affecting the source
+func (b *Bom) Clone() *Bom {
+	c := *b
+	c.conditions = b.conditions.clone()
+	c.options = b.options.clone()
+	c.pipeline = append(AggregateStages(nil), b.pipeline...)
+	c.selectArg = append([]interface{}(nil), b.selectArg...)
+	c.after = append([]interface{}(nil), b.after...)
+	c.before = append([]interface{}(nil), b.before...)
+	c.sort = make([]*Sort, 0, len(b.sort))
+	for _, sort := range b.sort {
+		sv := *sort
+		c.sort = append(c.sort, &sv)
+	}
+	if b.limit != nil {
+		limit := *b.limit
+		c.limit = &limit
+	}
+	return &c
+}
+
+// Session create new query from base bom (client, db, collection, model, context, timeout and options),
+// conditions, sort, select, pipeline, limit and hint of the source are not copied (hint depends on conditions)
+func (b *Bom) Session() *Bom {
+	return &Bom{
+		client:            b.client,
+		model:             b.model,
+		ctx:               b.ctx,
+		dbName:            b.dbName,
+		dbCollection:      b.dbCollection,
+		queryTimeout:      b.queryTimeout,
+		skipWhenUpdating:  b.skipWhenUpdating,
+		options:           b.options.clone(),
+		collation:         b.collation,
+		maxTime:           b.maxTime,
+		comment:           b.comment,
+		readPreference:    b.readPreference,
+		readConcern:       b.readConcern,
+		writeConcern:      b.writeConcern,
+		limit:             &Limit{Page: 1, Size: DefaultSize},
+		lowercaseSort:     b.lowercaseSort,
+		disableTieBreaker: b.disableTieBreaker,
+		countStrategy:     b.countStrategy,
+		concurrentCount:   b.concurrentCount,
+		cursorKey:         b.cursorKey,
+	}
+}
+
+// clone copy conditions
+func (c Conditions) clone() Conditions {
+	return Conditions{
+		whereConditions: append([]Filter(nil), c.whereConditions...),
+		orConditions:    append([]Filter(nil), c.orConditions...),
+	}
+}
+
+// clone copy driver options
+func (o Options) clone() Options {
+	return Options{
+		aggregateOptions:        append([]*options.AggregateOptions(nil), o.aggregateOptions...),
+		updateOptions:           append([]*options.UpdateOptions(nil), o.updateOptions...),
+		insertOptions:           append([]*options.InsertOneOptions(nil), o.insertOptions...),
+		insertManyOptions:       append([]*options.InsertManyOptions(nil), o.insertManyOptions...),
+		findOneOptions:          append([]*options.FindOneOptions(nil), o.findOneOptions...),
+		findOptions:             append([]*options.FindOptions(nil), o.findOptions...),
+		findOneAndUpdateOptions: append([]*options.FindOneAndUpdateOptions(nil), o.findOneAndUpdateOptions...),
+	}
+}
+
 // WithDB enrich database name
 func (b *Bom) WithDB(dbName string) *Bom {
 	b.dbName = dbName
@@ -151,11 +258,21 @@ func (b *Bom) WithTimeout(time time.Duration) *Bom {
 	return b
 }
 
-// Deprecated: WithContext method should not be used
+// WithContext set parent context for queries (cancellation, deadline and values are passed to the driver),
+// query timeout is applied as a cap on top of it
 func (b *Bom) WithContext(ctx context.Context) *Bom {
+	b.ctx = ctx
 	return b
 }
 
+// Context get parent context of queries
+func (b *Bom) Context() context.Context {
+	if b.ctx == nil {
+		return context.Background()
+	}
+	return b.ctx
+}
+
 // WithCondition set default condition
 func (b *Bom) WithCondition(condition interface{}) *Bom {
 	b.condition = condition
@@ -166,9 +283,11 @@ func (b *Bom) WithCondition(condition interface{}) *Bom {
 func (b *Bom) WithLimit(limit *Limit) *Bom {
 	if limit.Page > 0 {
 		b.limit.Page = limit.Page
+		b.limitSet = true
 	}
 	if limit.Size > 0 {
 		b.limit.Size = limit.Size
+		b.limitSet = true
 	}
 	return b
 }
@@ -179,6 +298,18 @@ func (b *Bom) WithSort(sort *Sort) *Bom {
 	return b
 }
 
+// WithLowercaseSort lowercase sort field names (compatibility with previous versions)
+func (b *Bom) WithLowercaseSort(lowercase bool) *Bom {
+	b.lowercaseSort = lowercase
+	return b
+}
+
+// WithTieBreaker enable or disable _id as the last sort key of paginated queries (enabled by default)
+func (b *Bom) WithTieBreaker(enabled bool) *Bom {
+	b.disableTieBreaker = !enabled
+	return b
+}
+
 // WithLastID set custom lastID
 func (b *Bom) WithLastID(lastID string) *Bom {
 	b.lastID = lastID
@@ -189,10 +320,57 @@ func (b *Bom) WithLastID(lastID string) *Bom {
 func (b *Bom) WithSize(size int32) *Bom {
 	if size > 0 {
 		b.limit.Size = size
+		b.limitSet = true
 	}
 	return b
 }
 
+// WithCollation set collation of queries (find, count, update, delete and aggregate operations)
+// example: bom.WithCollation(&options.Collation{Locale: "en", Strength: 2})
+func (b *Bom) WithCollation(collation *options.Collation) *Bom {
+	b.collation = collation
+	return b
+}
+
+// WithHint set index hint of queries (find, find one, count and aggregate operations)
+// example: bom.WithHint("name_1") or bom.WithHint(bson.D{{"name", 1}}),
+// update, delete, find one and update and find one and delete ignore it (not supported by driver)
+func (b *Bom) WithHint(index interface{}) *Bom {
+	b.hint = index
+	return b
+}
+
+// WithMaxTime set server side time limit of queries (maxTimeMS)
+func (b *Bom) WithMaxTime(d time.Duration) *Bom {
+	b.maxTime = d
+	return b
+}
+
+// WithComment set comment of queries which is shown in profiler and logs (find and aggregate operations)
+func (b *Bom) WithComment(comment string) *Bom {
+	b.comment = comment
+	return b
+}
+
+// WithReadPreference set read preference of queries example: bom.WithReadPreference(readpref.SecondaryPreferred())
+func (b *Bom) WithReadPreference(pref *readpref.ReadPref) *Bom {
+	b.readPreference = pref
+	return b
+}
+
+// WithReadConcern set read concern of queries example: bom.WithReadConcern(readconcern.Majority())
+func (b *Bom) WithReadConcern(rc *readconcern.ReadConcern) *Bom {
+	b.readConcern = rc
+	return b
+}
+
+// WithWriteConcern set write concern of insert, update and delete operations
+// example: bom.WithWriteConcern(writeconcern.New(writeconcern.WMajority()))
+func (b *Bom) WithWriteConcern(wc *writeconcern.WriteConcern) *Bom {
+	b.writeConcern = wc
+	return b
+}
+
 // SetUpdateOptions set custom update options
 func (b *Bom) SetUpdateOptions(opts ...*options.UpdateOptions) *Bom {
 	b.options.updateOptions = append(b.options.updateOptions, opts...)
@@ -223,6 +401,12 @@ func (b *Bom) SetInsertOptions(opts ...*options.InsertOneOptions) *Bom {
 	return b
 }
 
+// SetInsertManyOptions set custom insert many options
+func (b *Bom) SetInsertManyOptions(opts ...*options.InsertManyOptions) *Bom {
+	b.options.insertManyOptions = append(b.options.insertManyOptions, opts...)
+	return b
+}
+
 // SetFindOneOptions set custom find one options
 func (b *Bom) SetFindOneOptions(opts ...*options.FindOneOptions) *Bom {
 	b.options.findOneOptions = append(b.options.findOneOptions, opts...)
@@ -295,24 +479,169 @@ func (b *Bom) WhereLte(field string, value interface{}) *Bom {
 	return b
 }
 
-// Not Not Condition example: bom.Not("age", 30)
+// Not Not Condition example: bom.Not("age", 30) or with operator expression bom.Not("age", primitive.M{"$gt": 30})
 func (b *Bom) Not(field string, value interface{}) *Bom {
-	b.conditions.notConditions = append(b.conditions.notConditions, map[string]interface{}{"field": field, "value": value})
+	switch value.(type) {
+	case primitive.D, primitive.M, primitive.Regex:
+		b = b.whereConditions(field, NotConditionOperator, value)
+	default:
+		b = b.WhereFilter(Not(Eq(field, value)))
+	}
+	return b
+}
+
+// WhereNor none of filters match condition example: bom.WhereNor(bom.Eq("status", "a"), bom.Lt("age", 18))
+func (b *Bom) WhereNor(filters ...Filter) *Bom {
+	b = b.WhereFilter(Nor(filters...))
+	return b
+}
+
+// WhereExists field exists condition example: bom.WhereExists("email")
+func (b *Bom) WhereExists(field string) *Bom {
+	b = b.WhereFilter(Exists(field, true))
+	return b
+}
+
+// WhereNotExists field not exists condition example: bom.WhereNotExists("deletedAt")
+func (b *Bom) WhereNotExists(field string) *Bom {
+	b = b.WhereFilter(Exists(field, false))
+	return b
+}
+
+// WhereType field bson type condition example: bom.WhereType("age", bom.TypeInt, bom.TypeLong)
+func (b *Bom) WhereType(field string, t BSONType, more ...BSONType) *Bom {
+	b = b.WhereFilter(Type(field, t, more...))
 	return b
 }
 
 // WhereIn WhereIn Condition example: bom.WhereIn("age", 30)
 func (b *Bom) WhereIn(field string, value interface{}) *Bom {
-	b.conditions.inConditions = append(b.conditions.inConditions, map[string]interface{}{"field": field, "value": value})
+	b = b.whereConditions(field, InConditionOperator, value)
 	return b
 }
 
 // NotWhereIn not where in condition example: bom.NotWhereIn("age", 30)
 func (b *Bom) NotWhereIn(field string, value interface{}) *Bom {
-	b.conditions.notInConditions = append(b.conditions.notInConditions, map[string]interface{}{"field": field, "value": value})
+	b = b.whereConditions(field, NotInConditionOperator, value)
 	return b
 }
 
+// WhereAll array contains all values condition example: bom.WhereAll("tags", []string{"a", "b"})
+func (b *Bom) WhereAll(field string, values interface{}) *Bom {
+	b = b.whereConditions(field, AllConditionOperator, values)
+	return b
+}
+
+// WhereSize array length condition example: bom.WhereSize("tags", 2)
+func (b *Bom) WhereSize(field string, size int) *Bom {
+	b = b.whereConditions(field, SizeConditionOperator, size)
+	return b
+}
+
+// WhereElemMatch array element condition example: bom.WhereElemMatch("items", bom.And(bom.Eq("sku", "a"), bom.Gt("qty", 1)))
+func (b *Bom) WhereElemMatch(field string, filter Filter) *Bom {
+	b = b.WhereFilter(ElementMatch(field, filter))
+	return b
+}
+
+// OrWhereElemMatch or array element condition example: bom.OrWhereElemMatch("items", bom.Eq("sku", "a"))
+func (b *Bom) OrWhereElemMatch(field string, filter Filter) *Bom {
+	b = b.OrWhereFilter(ElementMatch(field, filter))
+	return b
+}
+
+// WhereNear near point condition, maxDistance in meters (0 is unlimited)
+// example: bom.WhereNear("location", bom.NewPoint(37.61, 55.75), 1000)
+func (b *Bom) WhereNear(field string, point Point, maxDistance float64) *Bom {
+	b = b.WhereFilter(Near(field, point, maxDistance))
+	return b
+}
+
+// WhereNearSphere near point on sphere condition, maxDistance in meters (0 is unlimited)
+func (b *Bom) WhereNearSphere(field string, point Point, maxDistance float64) *Bom {
+	b = b.WhereFilter(NearSphere(field, point, maxDistance))
+	return b
+}
+
+// WhereWithinPolygon within polygon condition example: bom.WhereWithinPolygon("location", bom.NewPolygon(ring))
+func (b *Bom) WhereWithinPolygon(field string, polygon Polygon) *Bom {
+	b = b.WhereFilter(GeoWithin(field, polygon))
+	return b
+}
+
+// WhereGeoWithin within geometry condition example: bom.WhereGeoWithin("location", bom.NewMultiPolygon(p1, p2))
+func (b *Bom) WhereGeoWithin(field string, geometry Geometry) *Bom {
+	b = b.WhereFilter(GeoWithin(field, geometry))
+	return b
+}
+
+// WhereGeoIntersects intersects geometry condition example: bom.WhereGeoIntersects("area", bom.NewPoint(37.61, 55.75))
+func (b *Bom) WhereGeoIntersects(field string, geometry Geometry) *Bom {
+	b = b.WhereFilter(GeoIntersects(field, geometry))
+	return b
+}
+
+// WhereExpr aggregation expression condition, allows to compare fields of the same document
+// example: bom.WhereExpr(bom.ExprGt(bom.ExprField("spent"), bom.ExprField("budget")))
+func (b *Bom) WhereExpr(expression Expression) *Bom {
+	b = b.WhereFilter(Expr(expression))
+	return b
+}
+
+// WhereRegex regular expression condition example: bom.WhereRegex("name", "^jo", "i")
+func (b *Bom) WhereRegex(field string, pattern string, options string) *Bom {
+	b = b.WhereFilter(Regex(field, pattern, options))
+	return b
+}
+
+// WhereLike substring condition, value is escaped example: bom.WhereLike("name", query, true)
+func (b *Bom) WhereLike(field string, value string, insensitive bool) *Bom {
+	b = b.WhereFilter(Like(field, value, insensitive))
+	return b
+}
+
+// WhereStartsWith prefix condition, value is escaped example: bom.WhereStartsWith("name", query, true)
+func (b *Bom) WhereStartsWith(field string, prefix string, insensitive bool) *Bom {
+	b = b.WhereFilter(StartsWith(field, prefix, insensitive))
+	return b
+}
+
+// WhereText full text search condition example: bom.WhereText("coffee", bom.TextLanguage("en")),
+// sort by score with bom.Sort{Field: "score", Type: bom.SortTypeTextScore} and select it with bom.TextScore("score")
+func (b *Bom) WhereText(search string, options ...TextOption) *Bom {
+	b = b.WhereFilter(Text(search, options...))
+	return b
+}
+
+// WhereFilter typed filters joined to query with and example: bom.WhereFilter(bom.Or(bom.Eq("a", 1), bom.Eq("b", 2)))
+func (b *Bom) WhereFilter(filters ...Filter) *Bom {
+	for _, filter := range filters {
+		if !isEmptyFilter(filter) {
+			b.conditions.whereConditions = append(b.conditions.whereConditions, filter)
+		}
+	}
+	return b
+}
+
+// OrWhereFilter typed filters joined to query with or example: bom.OrWhereFilter(bom.And(bom.Eq("a", 1), bom.Eq("b", 2)))
+func (b *Bom) OrWhereFilter(filters ...Filter) *Bom {
+	for _, filter := range filters {
+		if !isEmptyFilter(filter) {
+			b.conditions.orConditions = append(b.conditions.orConditions, filter)
+		}
+	}
+	return b
+}
+
+// Filter typed filter of query built from where conditions
+func (b *Bom) Filter() Filter {
+	filters := append([]Filter(nil), b.conditions.whereConditions...)
+	if len(b.conditions.orConditions) > 0 {
+		filters = append(filters, Or(b.conditions.orConditions...))
+	}
+	return And(filters...)
+}
+
 // OrWhereEq or where in condition example: bom.OrWhereEq("age", 30)
 func (b *Bom) OrWhereEq(field string, value interface{}) *Bom {
 	b = b.orWhereConditions(field, EqualConditionOperator, value)
@@ -355,7 +684,19 @@ func (b *Bom) OrWhere(field string, value interface{}) *Bom {
 	return b
 }
 
-// BuildProjection build projection
+// WhereGroup group of conditions joined to query with and,
+// example (a = 1 or b = 2): bom.WhereGroup(func(q *bom.Bom) { q.OrWhereEq("a", 1).OrWhereEq("b", 2) })
+func (b *Bom) WhereGroup(group func(q *Bom)) *Bom {
+	return b.WhereFilter(b.groupFilter(group))
+}
+
+// OrWhereGroup group of conditions joined to query with or,
+// example (a = 1 and b = 2): bom.OrWhereGroup(func(q *bom.Bom) { q.WhereEq("a", 1).WhereEq("b", 2) })
+func (b *Bom) OrWhereGroup(group func(q *Bom)) *Bom {
+	return b.OrWhereFilter(b.groupFilter(group))
+}
+
+// BuildProjection build projection, dot paths which are covered by parent field are skipped
 func (b *Bom) BuildProjection() primitive.M {
 	var result primitive.M
 	if len(b.selectArg) > 0 {
@@ -369,13 +710,17 @@ func (b *Bom) BuildProjection() primitive.M {
 			case ElemSlice:
 				result[v.Key] = primitive.M{"$slice": primitive.A{v.Offset, v.Limit}}
 			case ElemMatch:
-				if vo, ok := v.Val.(ElemMatch); ok {
+				switch vo := v.Val.(type) {
+				case ElemMatch:
 					var sub = make(primitive.M)
 					sub[ElMathConditionOperator] = primitive.M{vo.Key: vo.Val}
 					result[v.Key] = sub
+				case Filter:
+					result[v.Key] = primitive.M{ElMathConditionOperator: vo.Compile()}
 				}
 			}
 		}
+		removePathCollisions(result)
 	}
 	return result
 }
@@ -393,8 +738,8 @@ func (b *Bom) Update(entity interface{}) (*mongo.UpdateResult, error) {
 	}
 
 	upResult := primitive.D{
-		{"$set", eRes},
-		{"$currentDate", primitive.D{{"updatedat", true}}},
+		{Key: "$set", Value: eRes},
+		{Key: "$currentDate", Value: primitive.D{{Key: "updatedat", Value: true}}},
 	}
 
 	return b.UpdateRaw(upResult)
@@ -407,11 +752,11 @@ func (b *Bom) UpdateRaw(update interface{}) (*mongo.UpdateResult, error) {
 		return nil, err
 	}
 
-	// set default context
-	ctx, cancel := context.WithTimeout(context.Background(), b.queryTimeout)
+	// set query context
+	ctx, cancel := b.getContext()
 	defer cancel()
 
-	res, err := b.Mongo().UpdateOne(ctx, b.getCondition(), update, b.options.updateOptions...)
+	res, err := b.Mongo().UpdateOne(ctx, b.getCondition(), update, b.buildUpdateOptions()...)
 
 	err = callToAfterUpdate(b.model)
 	if err != nil {
@@ -428,11 +773,11 @@ func (b *Bom) InsertOne(document interface{}) (*mongo.InsertOneResult, error) {
 		return nil, err
 	}
 
-	// set default context
-	ctx, cancel := context.WithTimeout(context.Background(), b.queryTimeout)
+	// set query context
+	ctx, cancel := b.getContext()
 	defer cancel()
 
-	insertOneResult, err := b.Mongo().InsertOne(ctx, document, b.options.insertOptions...)
+	insertOneResult, err := b.Mongo().InsertOne(ctx, document, b.buildInsertOneOptions()...)
 	if err != nil {
 		return nil, err
 	}
@@ -455,11 +800,11 @@ func (b *Bom) InsertMany(documents []interface{}) (*mongo.InsertManyResult, erro
 		}
 	}
 
-	// set default context
-	ctx, cancel := context.WithTimeout(context.Background(), b.queryTimeout)
+	// set query context
+	ctx, cancel := b.getContext()
 	defer cancel()
 
-	insertManyResult, err := b.Mongo().InsertMany(ctx, documents)
+	insertManyResult, err := b.Mongo().InsertMany(ctx, documents, b.buildInsertManyOptions()...)
 	if err != nil {
 		return nil, err
 	}
@@ -481,16 +826,14 @@ func (b *Bom) FindOne(callback func(s *mongo.SingleResult) error) error {
 	if projection := b.BuildProjection(); projection != nil {
 		findOptions.SetProjection(projection)
 	}
-	if sm := b.getSort(); sm != nil {
+	if sm := b.getSort(); len(sm) > 0 {
 		findOptions.SetSort(sm)
 	}
-	b.options.findOneOptions = append(b.options.findOneOptions, findOptions)
-
-	// set default context
-	ctx, cancel := context.WithTimeout(context.Background(), b.queryTimeout)
+	// set query context
+	ctx, cancel := b.getContext()
 	defer cancel()
 
-	s := b.Mongo().FindOne(ctx, b.getCondition(), b.options.findOneOptions...)
+	s := b.Mongo().FindOne(ctx, b.getCondition(), b.buildFindOneOptions(findOptions)...)
 	return callback(s)
 }
 
@@ -502,20 +845,18 @@ func (b *Bom) FindOneAndUpdate(update interface{}) (*mongo.SingleResult, error)
 		return nil, err
 	}
 
-	// set default context
-	ctx, cancel := context.WithTimeout(context.Background(), b.queryTimeout)
+	// set query context
+	ctx, cancel := b.getContext()
 	defer cancel()
 
 	var findOptions = options.FindOneAndUpdate()
 	if projection := b.BuildProjection(); projection != nil {
 		findOptions.SetProjection(projection)
 	}
-	if sm := b.getSort(); sm != nil {
+	if sm := b.getSort(); len(sm) > 0 {
 		findOptions.SetSort(sm)
 	}
-	b.options.findOneAndUpdateOptions = append(b.options.findOneAndUpdateOptions, findOptions)
-
-	r := b.Mongo().FindOneAndUpdate(ctx, b.getCondition(), update, b.options.findOneAndUpdateOptions...)
+	r := b.Mongo().FindOneAndUpdate(ctx, b.getCondition(), update, b.buildFindOneAndUpdateOptions(findOptions)...)
 	if r.Err() != nil {
 		return nil, err
 	}
@@ -535,10 +876,10 @@ func (b *Bom) FindOneAndDelete() (*mongo.SingleResult, error) {
 		return nil, err
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), b.queryTimeout)
+	ctx, cancel := b.getContext()
 	defer cancel()
 
-	r := b.Mongo().FindOneAndDelete(ctx, b.getCondition())
+	r := b.Mongo().FindOneAndDelete(ctx, b.getCondition(), b.buildFindOneAndDeleteOptions()...)
 	if r.Err() != nil {
 		return nil, err
 	}
@@ -553,11 +894,11 @@ func (b *Bom) FindOneAndDelete() (*mongo.SingleResult, error) {
 // DeleteMany delete many
 func (b *Bom) DeleteMany() (*mongo.DeleteResult, error) {
 
-	// set default context
-	ctx, cancel := context.WithTimeout(context.Background(), b.queryTimeout)
+	// set query context
+	ctx, cancel := b.getContext()
 	defer cancel()
 
-	return b.Mongo().DeleteMany(ctx, b.getCondition())
+	return b.Mongo().DeleteMany(ctx, b.getCondition(), b.buildDeleteOptions()...)
 }
 
 // Delete delete item
@@ -567,11 +908,11 @@ func (b *Bom) Delete() (*mongo.DeleteResult, error) {
 		return nil, err
 	}
 
-	// set default context
-	ctx, cancel := context.WithTimeout(context.Background(), b.queryTimeout)
+	// set query context
+	ctx, cancel := b.getContext()
 	defer cancel()
 
-	r, err := b.Mongo().DeleteOne(ctx, b.getCondition())
+	r, err := b.Mongo().DeleteOne(ctx, b.getCondition(), b.buildDeleteOptions()...)
 	if err != nil {
 		return nil, err
 	}
@@ -586,31 +927,20 @@ func (b *Bom) Delete() (*mongo.DeleteResult, error) {
 
 // AggregateWithPagination pagination aggr
 func (b *Bom) AggregateWithPagination(callback func(c *mongo.Cursor) (int32, error)) (*Pagination, error) {
-	aggregateOpts := options.Aggregate()
-	aggregateOpts.SetAllowDiskUse(false)
-
 	pagination := NewPagination(b.limit.Page, b.limit.Size)
-	facet := NewFacetStage()
-	limit, offset := pagination.CalculateOffset()
-	facet.SetLimit(limit + offset)
-	facet.SetSkip(offset)
-	if sm := b.getSort(); sm != nil {
-		facet.SetSort(sm)
-	}
-
-	b.FillPipeline(facet)
-	b.options.aggregateOptions = append(b.options.aggregateOptions, aggregateOpts)
+	stages := b.paginationStages(pagination)
+	opts := b.paginationAggregateOptions()
 
-	pipeline, err := b.pipeline.Aggregate()
+	pipeline, err := stages.Aggregate()
 	if err != nil {
 		return nil, err
 	}
 
-	// set default context
-	ctx, cancel := context.WithTimeout(context.Background(), b.queryTimeout)
+	// set query context
+	ctx, cancel := b.getContext()
 	defer cancel()
 
-	cur, err := b.Mongo().Aggregate(ctx, pipeline, b.options.aggregateOptions...)
+	cur, err := b.Mongo().Aggregate(ctx, pipeline, opts...)
 	if err != nil {
 		return &Pagination{}, err
 	}
@@ -626,86 +956,236 @@ func (b *Bom) AggregateWithPagination(callback func(c *mongo.Cursor) (int32, err
 		return nil, err
 	}
 
-	return pagination.WithTotal(count), err
+	return pagination.WithTotal(int64(count)), err
 }
 
-// ListWithPagination list of items with pagination
-func (b *Bom) ListWithPagination(callback func(cursor *mongo.Cursor) error) (*Pagination, error) {
+// AggregateWithPaginationInto aggregation with pagination, items of page are decoded into results (pointer to slice)
+// and total is read from facet example: var users []User; p, err := bm.AggregateWithPaginationInto(&users)
+func (b *Bom) AggregateWithPaginationInto(results interface{}) (*Pagination, error) {
+	if rv := reflect.ValueOf(results); rv.Kind() != reflect.Ptr || rv.Elem().Kind() != reflect.Slice {
+		return nil, fmt.Errorf("%w: results must be pointer to slice", ErrInvalidValue)
+	}
+
 	pagination := NewPagination(b.limit.Page, b.limit.Size)
+	stages := b.paginationStages(pagination)
+	pipeline, err := stages.Aggregate()
+	if err != nil {
+		return nil, err
+	}
+
+	// set query context
+	ctx, cancel := b.getContext()
+	defer cancel()
+
+	cur, err := b.Mongo().Aggregate(ctx, pipeline, b.paginationAggregateOptions()...)
+	if err != nil {
+		return nil, err
+	}
+	defer cur.Close(ctx)
+
+	// facet returns single document
+	var facet bson.Raw
+	if cur.Next(ctx) {
+		facet = cur.Current
+	}
+	if err := cur.Err(); err != nil {
+		return nil, err
+	}
+
+	count, err := decodeFacet(facet, results)
+	if err != nil {
+		return nil, err
+	}
+	return pagination.WithTotal(count), nil
+}
+
+// decodeFacet internal method for decode result branch of pagination facet into results and read total
+func decodeFacet(facet bson.Raw, results interface{}) (int64, error) {
+	slice := reflect.ValueOf(results).Elem()
+	slice.Set(reflect.MakeSlice(slice.Type(), 0, 0))
+	if facet == nil {
+		return 0, nil
+	}
+
+	var page struct {
+		Result bson.RawValue `bson:"result"`
+		Total  []struct {
+			Count int64 `bson:"count"`
+		} `bson:"total"`
+	}
+	if err := bson.Unmarshal(facet, &page); err != nil {
+		return 0, err
+	}
+	if page.Result.Type == bsontype.Array {
+		if err := page.Result.Unmarshal(results); err != nil {
+			return 0, err
+		}
+	}
+	if len(page.Total) == 0 {
+		return 0, nil
+	}
+	return page.Total[0].Count, nil
+}
+
+// paginationStages internal method for build pipeline of AggregateWithPagination,
+// result branch of facet is sorted before skip and limit
+func (b *Bom) paginationStages(pagination *Pagination) AggregateStages {
+	facet := NewFacetStage()
+	limit, offset := pagination.CalculateOffset()
+	if sm := b.getPaginationSort(); len(sm) > 0 {
+		facet.SetSortD(sm)
+	}
+	facet.SetSkip(offset)
+	facet.SetLimit(limit)
+	return append(append(AggregateStages(nil), b.pipeline...), facet)
+}
+
+// paginationFindOptions internal method for build find options of ListWithPagination
+func (b *Bom) paginationFindOptions(pagination *Pagination) *options.FindOptions {
 	limit, offset := pagination.CalculateOffset()
 
 	var findOptions = options.Find()
 	findOptions.SetLimit(int64(limit)).SetSkip(int64(offset))
-	if sm := b.getSort(); sm != nil {
+	if sm := b.getPaginationSort(); len(sm) > 0 {
 		findOptions.SetSort(sm)
 	}
 	if projection := b.BuildProjection(); projection != nil {
 		findOptions.SetProjection(projection)
 	}
+	return findOptions
+}
 
+// listFindOptions internal method for build options of find query of ListWithPagination,
+// probe is true when next page is detected by one extra item
+func (b *Bom) listFindOptions(pagination *Pagination) (opts []*options.FindOptions, probe bool) {
+	findOptions := b.paginationFindOptions(pagination)
+
+	// total is not exact, so next page is detected by one extra item
+	mode := b.countMode(b.getCondition())
+	probe = mode == CountSkipped || mode == CountCapped
+	if probe {
+		findOptions.SetLimit(*findOptions.Limit + 1)
+	}
+	return b.buildFindOptions(findOptions), probe
+}
+
+// paginationAggregateOptions internal method for build options of aggregate query of AggregateWithPagination
+// and AggregateWithPaginationInto
+func (b *Bom) paginationAggregateOptions() []*options.AggregateOptions {
+	aggregateOpts := options.Aggregate()
+	aggregateOpts.SetAllowDiskUse(false)
+	return b.buildAggregateOptions(aggregateOpts)
+}
+
+// ListWithPagination list of items with pagination, total is counted by count strategy (see WithCountStrategy),
+// count and find are run in parallel when concurrent count is enabled (see WithConcurrentCount)
+func (b *Bom) ListWithPagination(callback func(cursor *mongo.Cursor) error) (*Pagination, error) {
 	condition := b.getCondition()
-	b.options.findOptions = append(b.options.findOptions, findOptions)
+	pagination := NewPagination(b.limit.Page, b.limit.Size)
+	pagination.Count = b.countMode(condition)
 
-	// set default context
-	ctx, cancel := context.WithTimeout(context.Background(), b.queryTimeout)
-	defer cancel()
+	opts, probe := b.listFindOptions(pagination)
 
 	var count int64
-	var err error
-	if condition != nil {
-		if bs, ok := condition.(primitive.M); ok {
-			if len(bs) > 0 {
-				count, err = b.Mongo().CountDocuments(ctx, condition)
-			} else {
-				count, err = b.Mongo().EstimatedDocumentCount(ctx)
+	var capped, more bool
+	var items int32
+	var callbackErr error
+	countTask := func(ctx context.Context) (err error) {
+		count, capped, err = b.countTotal(ctx, condition)
+		return err
+	}
+	findTask := func(ctx context.Context) (err error) {
+		items, more, callbackErr, err = b.findPage(ctx, condition, opts, pagination.Size, probe, callback)
+		return err
+	}
+
+	if b.concurrentCount {
+		// set query context, the deadline is shared by count and find
+		ctx, cancel := b.getContext()
+		defer cancel()
+
+		if err := runConcurrently(ctx, cancel, countTask, findTask); err != nil {
+			return &Pagination{}, err
+		}
+	} else {
+		for _, task := range []func(ctx context.Context) error{countTask, findTask} {
+			// set query context
+			ctx, cancel := b.getContext()
+			err := task(ctx)
+			cancel()
+			if err != nil {
+				return &Pagination{}, err
 			}
 		}
 	}
-	if err != nil {
-		return &Pagination{}, err
-	}
 
-	// set default context
-	ctx, cancel = context.WithTimeout(context.Background(), b.queryTimeout)
-	defer cancel()
+	pagination.WithTotal(count)
+	pagination.Capped = capped
+	if probe {
+		pagination.withPage(int64(items), more)
+	}
+	return pagination, callbackErr
+}
 
-	cur, err := b.Mongo().Find(ctx, condition, b.options.findOptions...)
+// findPage internal method for iterate items of page, extra item of probe query is not passed to callback
+func (b *Bom) findPage(ctx context.Context, condition interface{}, opts []*options.FindOptions, size int32, probe bool,
+	callback func(cursor *mongo.Cursor) error) (items int32, more bool, callbackErr error, err error) {
+	cur, err := b.Mongo().Find(ctx, condition, opts...)
 	if err != nil {
-		return &Pagination{}, err
+		return 0, false, nil, err
 	}
 	defer cur.Close(ctx)
+
 	for cur.Next(ctx) {
-		err = callback(cur)
+		if probe && items == size {
+			more = true
+			break
+		}
+		callbackErr = callback(cur)
+		items++
 	}
 	if err := cur.Err(); err != nil {
-		return &Pagination{}, err
+		return 0, false, nil, err
 	}
-	return pagination.WithTotal(int32(count)), err
+	return items, more, callbackErr, nil
 }
 
-// ListWithLastID iteration method for deep pagination
+// ListWithLastID iteration method for deep pagination by _id (ascending unless sort by _id desc is set),
+// see ListWithKeyset for pagination by other sort keys
 func (b *Bom) ListWithLastID(callback func(cursor *mongo.Cursor) error) (lastID string, err error) {
 
 	lastID = b.lastID
 	findOptions := options.Find()
-	findOptions.SetLimit(int64(b.limit.Size))
+	// one extra item is fetched to detect next page
+	findOptions.SetLimit(int64(b.limit.Size) + 1)
 
-	if sm := b.getSort(); sm != nil {
-		findOptions.SetSort(sm)
+	sm := b.getSort()
+	if len(sm) == 0 {
+		sm = primitive.D{{Key: "_id", Value: int32(1)}}
 	}
+	findOptions.SetSort(sm)
 	if projection := b.BuildProjection(); projection != nil {
 		findOptions.SetProjection(projection)
 	}
 
+	// work on a copy, so lastID condition does not leak into the source bom
+	q := b.Clone()
 	if lastID != "" {
-		b.whereConditions("_id", GreaterConditionOperator, ToObj(lastID))
+		operator := GreaterConditionOperator
+		for _, e := range sm {
+			if e.Key == "_id" && e.Value == SortTypeMatcher["desc"] {
+				operator = LessConditionOperator
+			}
+		}
+		q.whereConditions("_id", operator, ToObj(lastID))
 	}
+	condition := q.getCondition()
 
-	// set default context
-	ctx, cancel := context.WithTimeout(context.Background(), b.queryTimeout)
+	// set query context
+	ctx, cancel := b.getContext()
 	defer cancel()
 
-	cur, err := b.Mongo().Find(ctx, b.getCondition(), findOptions)
+	cur, err := b.Mongo().Find(ctx, condition, b.buildFindOptions(findOptions)...)
 	if err != nil {
 		return "", err
 	}
@@ -718,35 +1198,22 @@ func (b *Bom) ListWithLastID(callback func(cursor *mongo.Cursor) error) (lastID
 	}()
 
 	var lastElement primitive.ObjectID
+	var n int32
+	hasNext := false
 	for cur.Next(ctx) {
+		if n == b.limit.Size {
+			hasNext = true
+			break
+		}
 		err = callback(cur)
 		lastElement = cur.Current.Lookup("_id").ObjectID()
+		n++
 	}
 	if err := cur.Err(); err != nil {
 		return "", err
 	}
 
-	// set default context
-	ctx, cancel = context.WithTimeout(context.Background(), b.queryTimeout)
-	defer cancel()
-
-	var count int64
-	if b.getCondition() != nil {
-		if bs, ok := b.getCondition().(primitive.M); ok {
-			if len(bs) > 0 {
-				count, err = b.Mongo().CountDocuments(ctx, b.getCondition())
-			} else {
-				count, err = b.Mongo().EstimatedDocumentCount(ctx)
-			}
-		}
-	} else {
-		count, err = b.Mongo().EstimatedDocumentCount(ctx)
-	}
-	if err != nil {
-		return "", err
-	}
-
-	if count > int64(b.limit.Size) {
+	if hasNext {
 		return lastElement.Hex(), err
 	}
 
@@ -759,15 +1226,15 @@ func (b *Bom) List(callback func(cursor *mongo.Cursor) error) error {
 	if projection := b.BuildProjection(); projection != nil {
 		findOptions.SetProjection(projection)
 	}
-	if sm := b.getSort(); sm != nil {
+	if sm := b.getSort(); len(sm) > 0 {
 		findOptions.SetSort(sm)
 	}
 
-	// set default context
-	ctx, cancel := context.WithTimeout(context.Background(), b.queryTimeout)
+	// set query context
+	ctx, cancel := b.getContext()
 	defer cancel()
 
-	cur, err := b.Mongo().Find(ctx, b.getCondition(), findOptions)
+	cur, err := b.Mongo().Find(ctx, b.getCondition(), b.buildFindOptions(findOptions)...)
 	if err != nil {
 		return err
 	}
@@ -785,67 +1252,25 @@ func (b *Bom) List(callback func(cursor *mongo.Cursor) error) error {
 	return err
 }
 
-// conditionTransformer internal method for transform condition
-func (b *Bom) conditionTransformer(field string, operator string, value interface{}) map[string]interface{} {
-	var c = make(map[string]interface{})
-	c["field"] = field
-	c["value"] = value
-	if operator != EqualConditionOperator {
-		c["value"] = primitive.D{{Key: operator, Value: value}}
-	}
-	return c
+// groupFilter internal method for build filter of group conditions
+func (b *Bom) groupFilter(group func(q *Bom)) Filter {
+	q := b.Session()
+	group(q)
+	return q.Filter()
 }
 
 // whereConditions internal method for build or condition
 func (b *Bom) orWhereConditions(field string, conditions string, value interface{}) *Bom {
-	b.conditions.orConditions = append(b.conditions.orConditions, b.conditionTransformer(field, conditions, value))
+	b.conditions.orConditions = append(b.conditions.orConditions, Condition(field, conditions, value))
 	return b
 }
 
 // whereConditions internal method for build condition
 func (b *Bom) whereConditions(field string, conditions string, value interface{}) *Bom {
-	b.conditions.whereConditions = append(b.conditions.whereConditions, b.conditionTransformer(field, conditions, value))
+	b.conditions.whereConditions = append(b.conditions.whereConditions, Condition(field, conditions, value))
 	return b
 }
 
-// buildCondition internal build condition method
-func (b *Bom) buildCondition() interface{} {
-	result := make(primitive.M)
-	if len(b.conditions.whereConditions) > 0 {
-		var query []primitive.M
-		for _, cnd := range b.conditions.whereConditions {
-			field := cnd["field"]
-			value := cnd["value"]
-			query = append(query, primitive.M{field.(string): value})
-		}
-		result[AndConditionOperator] = query
-	}
-	if len(b.conditions.orConditions) > 0 {
-		var query []primitive.M
-		for _, cnd := range b.conditions.orConditions {
-			field := cnd["field"]
-			value := cnd["value"]
-			query = append(query, primitive.M{field.(string): value})
-		}
-		result[OrConditionOperator] = query
-	}
-	if len(b.conditions.inConditions) > 0 {
-		for _, cnd := range b.conditions.inConditions {
-			field := cnd["field"]
-			value := cnd["value"]
-			result[field.(string)] = primitive.M{InConditionOperator: value}
-		}
-	}
-	if len(b.conditions.notInConditions) > 0 {
-		for _, cnd := range b.conditions.notInConditions {
-			field := cnd["field"]
-			value := cnd["value"]
-			result[field.(string)] = primitive.M{NotInConditionOperator: value}
-		}
-	}
-	return result
-}
-
 // structToMap struct to map
 func (b *Bom) structToMap(i interface{}) (map[string]interface{}, error) {
 	result := make(map[string]interface{})
@@ -866,23 +1291,63 @@ func (b *Bom) structToMap(i interface{}) (map[string]interface{}, error) {
 	return correct, nil
 }
 
-// getSort get sort object
-func (b *Bom) getSort() map[string]interface{} {
-	var sortMap map[string]interface{}
-	if len(b.sort) > 0 {
-		sortMap = make(map[string]interface{})
-		for _, sort := range b.sort {
-			if len(sort.Field) > 0 {
-				sortMap[strings.ToLower(sort.Field)] = 1
-				if len(sort.Type) > 0 {
-					if val, ok := SortTypeMatcher[strings.ToLower(sort.Type)]; ok {
-						sortMap[strings.ToLower(sort.Field)] = val
-					}
-				}
-			}
+// getContext create query context from parent context limited by query timeout
+func (b *Bom) getContext() (context.Context, context.CancelFunc) {
+	if b.queryTimeout <= 0 {
+		return context.WithCancel(b.Context())
+	}
+	return context.WithTimeout(b.Context(), b.queryTimeout)
+}
+
+// getSort get ordered sort document, field names are kept as given unless lowercase sort is enabled
+func (b *Bom) getSort() primitive.D {
+	var sortDoc primitive.D
+	for _, sort := range b.sort {
+		if len(sort.Field) == 0 {
+			continue
+		}
+		field := sort.Field
+		if b.lowercaseSort {
+			field = strings.ToLower(field)
 		}
+		var value interface{} = int32(1)
+		if strings.EqualFold(sort.Type, SortTypeTextScore) {
+			value = primitive.M{MetaOperator: SortTypeTextScore}
+		} else if val, ok := SortTypeMatcher[strings.ToLower(sort.Type)]; ok {
+			value = val
+		}
+		sortDoc = setSortKey(sortDoc, field, value)
+	}
+	return sortDoc
+}
+
+// getPaginationSort get sort document of paginated queries, _id is added as the last key to make order stable
+func (b *Bom) getPaginationSort() primitive.D {
+	sortDoc := b.getSort()
+	if len(sortDoc) == 0 || b.disableTieBreaker {
+		return sortDoc
 	}
-	return sortMap
+	for _, e := range sortDoc {
+		if e.Key == "_id" {
+			return sortDoc
+		}
+	}
+	direction := int32(1)
+	if val, ok := sortDoc[len(sortDoc)-1].Value.(int32); ok {
+		direction = val
+	}
+	return append(sortDoc, primitive.E{Key: "_id", Value: direction})
+}
+
+// setSortKey set sort key, repeated key replaces the previous value in place
+func setSortKey(sortDoc primitive.D, key string, value interface{}) primitive.D {
+	for i := range sortDoc {
+		if sortDoc[i].Key == key {
+			sortDoc[i].Value = value
+			return sortDoc
+		}
+	}
+	return append(sortDoc, primitive.E{Key: key, Value: value})
 }
 
 // getCondition common condition builder method
@@ -890,11 +1355,18 @@ func (b *Bom) getCondition() interface{} {
 	if b.condition != nil {
 		return b.condition
 	}
-	bc := b.buildCondition()
-	if bc != nil {
-		if val, ok := bc.(primitive.M); ok {
-			return val
-		}
-	}
-	return primitive.M{}
+	return b.Filter().Compile()
+}
+
+// isEmptyCondition check that condition matches all documents
+func isEmptyCondition(condition interface{}) bool {
+	switch c := condition.(type) {
+	case nil:
+		return true
+	case primitive.M:
+		return len(c) == 0
+	case primitive.D:
+		return len(c) == 0
+	}
+	return false
 }
@@ -0,0 +1,208 @@
+package bom
+
+import (
+	"reflect"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestBom_Clone(t *testing.T) {
+	base := &Bom{limit: &Limit{Page: 1, Size: DefaultSize}}
+	base.WhereEq("status", "active").WithSort(&Sort{Field: "name", Type: "asc"})
+
+	clone := base.Clone()
+	clone.WhereGt("age", 30).WithSort(&Sort{Field: "age", Type: "desc"}).WithLimit(&Limit{Page: 3})
+	clone.sort[0].Type = "desc"
+
+	want := primitive.D{{Key: "status", Value: "active"}}
+	if got := base.getCondition(); !reflect.DeepEqual(got, want) {
+		t.Errorf("getCondition() = %v, want %v", got, want)
+	}
+	if len(base.sort) != 1 || base.sort[0].Type != "asc" {
+		t.Errorf("sort of source bom was changed: %v", base.sort)
+	}
+	if base.limit.Page != 1 {
+		t.Errorf("limit of source bom was changed: %v", base.limit)
+	}
+}
+
+func TestBom_Session(t *testing.T) {
+	base := &Bom{dbName: "db", dbCollection: "users", limit: &Limit{Page: 2, Size: 5}}
+	base.WhereEq("status", "active")
+
+	session := base.Session()
+	if session.dbName != "db" || session.dbCollection != "users" {
+		t.Errorf("Session() lost base configuration: %v, %v", session.dbName, session.dbCollection)
+	}
+	if got := session.getCondition(); !reflect.DeepEqual(got, primitive.D{}) {
+		t.Errorf("Session() condition = %v, want empty", got)
+	}
+	if session.limit.Page != 1 || session.limit.Size != DefaultSize {
+		t.Errorf("Session() limit = %v, want default", session.limit)
+	}
+}
+
+func TestBom_WhereGroup(t *testing.T) {
+	tests := []struct {
+		name  string
+		build func(b *Bom)
+		want  primitive.D
+	}{
+		{name: "and of or groups", build: func(b *Bom) {
+			b.WhereGroup(func(q *Bom) { q.OrWhereEq("a", 1).OrWhereEq("b", 2) }).
+				WhereGroup(func(q *Bom) { q.OrWhereEq("c", 3).OrWhereEq("d", 4) })
+		}, want: primitive.D{{Key: AndConditionOperator, Value: primitive.A{
+			primitive.D{{Key: OrConditionOperator, Value: primitive.A{primitive.D{{Key: "a", Value: 1}}, primitive.D{{Key: "b", Value: 2}}}}},
+			primitive.D{{Key: OrConditionOperator, Value: primitive.A{primitive.D{{Key: "c", Value: 3}}, primitive.D{{Key: "d", Value: 4}}}}},
+		}}}},
+		{name: "or containing and", build: func(b *Bom) {
+			b.OrWhereGroup(func(q *Bom) { q.WhereEq("a", 1).WhereGt("b", 2) }).OrWhereEq("c", 3)
+		}, want: primitive.D{{Key: OrConditionOperator, Value: primitive.A{
+			primitive.D{{Key: AndConditionOperator, Value: primitive.A{
+				primitive.D{{Key: "a", Value: 1}},
+				primitive.D{{Key: "b", Value: primitive.D{{Key: GreaterConditionOperator, Value: 2}}}},
+			}}},
+			primitive.D{{Key: "c", Value: 3}},
+		}}}},
+		{name: "where and or conditions", build: func(b *Bom) {
+			b.WhereIn("status", []string{"a", "b"}).OrWhereEq("c", 3).OrWhereEq("d", 4)
+		}, want: primitive.D{{Key: AndConditionOperator, Value: primitive.A{
+			primitive.D{{Key: "status", Value: primitive.D{{Key: InConditionOperator, Value: []string{"a", "b"}}}}},
+			primitive.D{{Key: OrConditionOperator, Value: primitive.A{primitive.D{{Key: "c", Value: 3}}, primitive.D{{Key: "d", Value: 4}}}}},
+		}}}},
+		{name: "empty group is skipped", build: func(b *Bom) {
+			b.WhereEq("a", 1).WhereGroup(func(q *Bom) {})
+		}, want: primitive.D{{Key: "a", Value: 1}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := &Bom{limit: &Limit{Page: 1, Size: DefaultSize}}
+			tt.build(b)
+			if got := b.getCondition(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("getCondition() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBom_NegationAndTypeConditions(t *testing.T) {
+	tests := []struct {
+		name  string
+		build func(b *Bom)
+		want  primitive.D
+	}{
+		{name: "not value", build: func(b *Bom) { b.Not("age", 30) },
+			want: primitive.D{{Key: "age", Value: primitive.D{{Key: "$ne", Value: 30}}}}},
+		{name: "not operator expression", build: func(b *Bom) { b.Not("age", primitive.M{"$gt": 30}) },
+			want: primitive.D{{Key: "age", Value: primitive.D{{Key: "$not", Value: primitive.M{"$gt": 30}}}}}},
+		{name: "nor", build: func(b *Bom) { b.WhereNor(Eq("status", "a"), Lt("age", 18)) },
+			want: primitive.D{{Key: "$nor", Value: primitive.A{
+				primitive.D{{Key: "status", Value: "a"}},
+				primitive.D{{Key: "age", Value: primitive.D{{Key: "$lt", Value: 18}}}},
+			}}}},
+		{name: "exists", build: func(b *Bom) { b.WhereExists("email") },
+			want: primitive.D{{Key: "email", Value: primitive.D{{Key: "$exists", Value: true}}}}},
+		{name: "not exists", build: func(b *Bom) { b.WhereNotExists("deletedAt") },
+			want: primitive.D{{Key: "deletedAt", Value: primitive.D{{Key: "$exists", Value: false}}}}},
+		{name: "type", build: func(b *Bom) { b.WhereType("age", TypeInt) },
+			want: primitive.D{{Key: "age", Value: primitive.D{{Key: "$type", Value: TypeInt}}}}},
+		{name: "multiple types", build: func(b *Bom) { b.WhereType("age", TypeInt, TypeLong) },
+			want: primitive.D{{Key: "age", Value: primitive.D{{Key: "$type", Value: []BSONType{TypeInt, TypeLong}}}}}},
+		{name: "combined", build: func(b *Bom) { b.WhereEq("a", 1).Not("b", 2).WhereExists("c") },
+			want: primitive.D{{Key: "$and", Value: primitive.A{
+				primitive.D{{Key: "a", Value: 1}},
+				primitive.D{{Key: "b", Value: primitive.D{{Key: "$ne", Value: 2}}}},
+				primitive.D{{Key: "c", Value: primitive.D{{Key: "$exists", Value: true}}}},
+			}}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := &Bom{limit: &Limit{Page: 1, Size: DefaultSize}}
+			tt.build(b)
+			if got := b.getCondition(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("getCondition() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBom_ArrayConditions(t *testing.T) {
+	tests := []struct {
+		name  string
+		build func(b *Bom)
+		want  primitive.D
+	}{
+		{name: "all", build: func(b *Bom) { b.WhereAll("tags", []string{"a", "b"}) },
+			want: primitive.D{{Key: "tags", Value: primitive.D{{Key: "$all", Value: []string{"a", "b"}}}}}},
+		{name: "size", build: func(b *Bom) { b.WhereSize("tags", 2) },
+			want: primitive.D{{Key: "tags", Value: primitive.D{{Key: "$size", Value: 2}}}}},
+		{name: "elem match", build: func(b *Bom) { b.WhereElemMatch("items", And(Eq("sku", "a"), Gt("qty", 1))) },
+			want: primitive.D{{Key: "items", Value: primitive.D{{Key: "$elemMatch", Value: primitive.D{{Key: "$and", Value: primitive.A{
+				primitive.D{{Key: "sku", Value: "a"}},
+				primitive.D{{Key: "qty", Value: primitive.D{{Key: "$gt", Value: 1}}}},
+			}}}}}}}},
+		{name: "or elem match", build: func(b *Bom) { b.OrWhereElemMatch("items", Eq("sku", "a")).OrWhereEq("featured", true) },
+			want: primitive.D{{Key: "$or", Value: primitive.A{
+				primitive.D{{Key: "items", Value: primitive.D{{Key: "$elemMatch", Value: primitive.D{{Key: "sku", Value: "a"}}}}}},
+				primitive.D{{Key: "featured", Value: true}},
+			}}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := &Bom{limit: &Limit{Page: 1, Size: DefaultSize}}
+			tt.build(b)
+			if got := b.getCondition(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("getCondition() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBom_getSort(t *testing.T) {
+	tests := []struct {
+		name      string
+		build     func(b *Bom)
+		want      primitive.D
+		wantPaged primitive.D
+	}{
+		{name: "ordered and case preserving", build: func(b *Bom) {
+			b.WithSort(&Sort{Field: "createdAt", Type: "desc"}).WithSort(&Sort{Field: "name"}).WithSort(&Sort{Field: "age", Type: "ASC"})
+		},
+			want:      primitive.D{{Key: "createdAt", Value: int32(-1)}, {Key: "name", Value: int32(1)}, {Key: "age", Value: int32(1)}},
+			wantPaged: primitive.D{{Key: "createdAt", Value: int32(-1)}, {Key: "name", Value: int32(1)}, {Key: "age", Value: int32(1)}, {Key: "_id", Value: int32(1)}}},
+		{name: "lowercase compatibility", build: func(b *Bom) {
+			b.WithLowercaseSort(true).WithSort(&Sort{Field: "createdAt", Type: "desc"})
+		},
+			want:      primitive.D{{Key: "createdat", Value: int32(-1)}},
+			wantPaged: primitive.D{{Key: "createdat", Value: int32(-1)}, {Key: "_id", Value: int32(-1)}}},
+		{name: "repeated field replaces value", build: func(b *Bom) {
+			b.WithSort(&Sort{Field: "a"}).WithSort(&Sort{Field: "_id", Type: "desc"}).WithSort(&Sort{Field: "a", Type: "desc"})
+		},
+			want:      primitive.D{{Key: "a", Value: int32(-1)}, {Key: "_id", Value: int32(-1)}},
+			wantPaged: primitive.D{{Key: "a", Value: int32(-1)}, {Key: "_id", Value: int32(-1)}}},
+		{name: "text score", build: func(b *Bom) {
+			b.WithSort(&Sort{Field: "score", Type: SortTypeTextScore})
+		},
+			want:      primitive.D{{Key: "score", Value: primitive.M{"$meta": "textScore"}}},
+			wantPaged: primitive.D{{Key: "score", Value: primitive.M{"$meta": "textScore"}}, {Key: "_id", Value: int32(1)}}},
+		{name: "tie breaker disabled", build: func(b *Bom) {
+			b.WithTieBreaker(false).WithSort(&Sort{Field: "name"})
+		},
+			want:      primitive.D{{Key: "name", Value: int32(1)}},
+			wantPaged: primitive.D{{Key: "name", Value: int32(1)}}},
+		{name: "no sort", build: func(b *Bom) {}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := &Bom{limit: &Limit{Page: 1, Size: DefaultSize}}
+			tt.build(b)
+			if got := b.getSort(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("getSort() = %v, want %v", got, tt.want)
+			}
+			if got := b.getPaginationSort(); !reflect.DeepEqual(got, tt.wantPaged) {
+				t.Errorf("getPaginationSort() = %v, want %v", got, tt.wantPaged)
+			}
+		})
+	}
+}
@@ -8,6 +8,31 @@ var SkipWhenUpdating = map[string]bool{"id": true, "createdat": true, "updatedat
 // SortTypeMatcher value matcher
 var SortTypeMatcher = map[string]int32{"asc": 1, "desc": -1}
 
+// SortTypeTextScore sort type by full text search score
+const SortTypeTextScore = "textScore"
+
+// BSONType bson type alias for $type condition
+type BSONType string
+
+// Define bson type aliases
+const (
+	TypeDouble    BSONType = "double"
+	TypeString    BSONType = "string"
+	TypeObject    BSONType = "object"
+	TypeArray     BSONType = "array"
+	TypeBinary    BSONType = "binData"
+	TypeObjectID  BSONType = "objectId"
+	TypeBool      BSONType = "bool"
+	TypeDate      BSONType = "date"
+	TypeNull      BSONType = "null"
+	TypeRegex     BSONType = "regex"
+	TypeInt       BSONType = "int"
+	TypeTimestamp BSONType = "timestamp"
+	TypeLong      BSONType = "long"
+	TypeDecimal   BSONType = "decimal"
+	TypeNumber    BSONType = "number"
+)
+
 // StageInterface interface for stage
 type StageInterface interface {
 	GetStage() primitive.M
@@ -56,9 +81,45 @@ const (
 	// TypeConditionOperator mongo db operator
 	TypeConditionOperator = "$type"
 
+	// AllConditionOperator mongo db operator
+	AllConditionOperator = "$all"
+
+	// SizeConditionOperator mongo db operator
+	SizeConditionOperator = "$size"
+
+	// NearConditionOperator mongo db operator
+	NearConditionOperator = "$near"
+
+	// NearSphereConditionOperator mongo db operator
+	NearSphereConditionOperator = "$nearSphere"
+
+	// GeoWithinConditionOperator mongo db operator
+	GeoWithinConditionOperator = "$geoWithin"
+
+	// GeoIntersectsConditionOperator mongo db operator
+	GeoIntersectsConditionOperator = "$geoIntersects"
+
+	// GeometryOperator mongo db operator
+	GeometryOperator = "$geometry"
+
+	// ExprConditionOperator mongo db operator
+	ExprConditionOperator = "$expr"
+
+	// RegexConditionOperator mongo db operator
+	RegexConditionOperator = "$regex"
+
+	// TextConditionOperator mongo db operator
+	TextConditionOperator = "$text"
+
+	// MetaOperator mongo db operator
+	MetaOperator = "$meta"
+
 	// LookupAggregateOperator mongo db operator
 	LookupAggregateOperator = "$lookup"
 
+	// GeoNearAggregateOperator mongo db operator
+	GeoNearAggregateOperator = "$geoNear"
+
 	// FacetAggregateOperator mongo db operator
 	FacetAggregateOperator = "$facet"
 
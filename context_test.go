@@ -0,0 +1,78 @@
+package bom
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+type contextKey string
+
+func TestBom_getContext(t *testing.T) {
+	t.Run("parent cancellation", func(t *testing.T) {
+		parent, cancelParent := context.WithCancel(context.WithValue(context.Background(), contextKey("k"), "v"))
+		b := (&Bom{queryTimeout: time.Minute}).WithContext(parent)
+
+		ctx, cancel := b.getContext()
+		defer cancel()
+		if got := ctx.Value(contextKey("k")); got != "v" {
+			t.Errorf("getContext() value = %v, want v", got)
+		}
+		cancelParent()
+		select {
+		case <-ctx.Done():
+			if !errors.Is(ctx.Err(), context.Canceled) {
+				t.Errorf("getContext() error = %v, want %v", ctx.Err(), context.Canceled)
+			}
+		case <-time.After(time.Second):
+			t.Errorf("getContext() was not cancelled by parent")
+		}
+	})
+
+	t.Run("query timeout on top of parent", func(t *testing.T) {
+		parent, cancelParent := context.WithTimeout(context.Background(), time.Hour)
+		defer cancelParent()
+		b := (&Bom{queryTimeout: 10 * time.Millisecond}).WithContext(parent)
+
+		ctx, cancel := b.getContext()
+		defer cancel()
+		deadline, ok := ctx.Deadline()
+		if !ok || time.Until(deadline) > time.Second {
+			t.Errorf("getContext() deadline = %v, want query timeout", deadline)
+		}
+		<-ctx.Done()
+		if !errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			t.Errorf("getContext() error = %v, want %v", ctx.Err(), context.DeadlineExceeded)
+		}
+	})
+
+	t.Run("parent deadline is kept", func(t *testing.T) {
+		parent, cancelParent := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancelParent()
+		b := (&Bom{queryTimeout: time.Hour}).WithContext(parent)
+
+		ctx, cancel := b.getContext()
+		defer cancel()
+		if want, _ := parent.Deadline(); !deadlineEqual(ctx, want) {
+			t.Errorf("getContext() does not keep parent deadline %v", want)
+		}
+	})
+
+	t.Run("default context", func(t *testing.T) {
+		if (&Bom{}).Context() != context.Background() {
+			t.Errorf("Context() is not background without parent")
+		}
+		ctx, cancel := (&Bom{}).getContext()
+		defer cancel()
+		if _, ok := ctx.Deadline(); ok {
+			t.Errorf("getContext() without timeout has deadline")
+		}
+	})
+}
+
+// deadlineEqual internal method for check deadline of context
+func deadlineEqual(ctx context.Context, want time.Time) bool {
+	got, ok := ctx.Deadline()
+	return ok && got.Equal(want)
+}
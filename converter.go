@@ -12,6 +12,11 @@ func ElSlice(key string, limit, offset int32) ElemSlice {
 	return ElemSlice{Key: key, Limit: limit, Offset: offset}
 }
 
+// TextScore create projection of full text search score example: bm.Select("name", bom.TextScore("score"))
+func TextScore(key string) primitive.E {
+	return primitive.E{Key: key, Value: primitive.M{MetaOperator: SortTypeTextScore}}
+}
+
 // ToObj convert string to ObjectID
 func ToObj(val string) primitive.ObjectID {
 	objectID, _ := primitive.ObjectIDFromHex(val)
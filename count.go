@@ -0,0 +1,314 @@
+package bom
+
+import (
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// CountMode way of counting total items of paginated list
+type CountMode int
+
+// Define count modes
+const (
+	// CountExact CountDocuments (EstimatedDocumentCount for empty filter)
+	CountExact CountMode = iota
+	// CountEstimated EstimatedDocumentCount from collection metadata for unfiltered list,
+	// filtered list is not counted (as CountSkipped), next page is detected by one extra item
+	CountEstimated
+	// CountCapped CountDocuments which stops at cap, total is "at least cap" when reached
+	CountCapped
+	// CountSkipped no count, next page is detected by fetching one extra item
+	CountSkipped
+	// CountCached exact count which is cached for ttl by collection, filter, collation and hint
+	CountCached
+)
+
+var countModeNames = map[CountMode]string{
+	CountExact:     "exact",
+	CountEstimated: "estimated",
+	CountCapped:    "capped",
+	CountSkipped:   "skipped",
+	CountCached:    "cached",
+}
+
+// String name of count mode
+func (m CountMode) String() string {
+	if name, ok := countModeNames[m]; ok {
+		return name
+	}
+	return fmt.Sprintf("CountMode(%d)", int(m))
+}
+
+// MarshalText name of count mode for json
+func (m CountMode) MarshalText() ([]byte, error) {
+	return []byte(m.String()), nil
+}
+
+// CountStrategy count strategy of ListWithPagination
+type CountStrategy struct {
+	Mode CountMode
+	// Cap max count of CountCapped mode
+	Cap int64
+	// TTL cache time of CountCached mode
+	TTL time.Duration
+}
+
+// ExactCount count strategy of exact total (default)
+func ExactCount() CountStrategy {
+	return CountStrategy{Mode: CountExact}
+}
+
+// EstimatedCount count strategy of estimated total of collection, filtered list is not counted (see CountEstimated)
+func EstimatedCount() CountStrategy {
+	return CountStrategy{Mode: CountEstimated}
+}
+
+// CappedCount count strategy of total which stops at max example: bom.CappedCount(10000) for "10,000+"
+func CappedCount(max int64) CountStrategy {
+	return CountStrategy{Mode: CountCapped, Cap: max}
+}
+
+// SkippedCount count strategy without total, only next page is detected
+func SkippedCount() CountStrategy {
+	return CountStrategy{Mode: CountSkipped}
+}
+
+// CachedCount count strategy of exact total which is cached for ttl
+func CachedCount(ttl time.Duration) CountStrategy {
+	return CountStrategy{Mode: CountCached, TTL: ttl}
+}
+
+// WithCountStrategy set count strategy of ListWithPagination example: bom.WithCountStrategy(bom.CappedCount(10000))
+func (b *Bom) WithCountStrategy(strategy CountStrategy) *Bom {
+	b.countStrategy = strategy
+	return b
+}
+
+// WithConcurrentCount run count and find of ListWithPagination in parallel with shared deadline,
+// failure of one query cancels the other, callback is called from separate goroutine
+func (b *Bom) WithConcurrentCount(enabled bool) *Bom {
+	b.concurrentCount = enabled
+	return b
+}
+
+// CountCacheSize max entries of count cache, least recently used entries are evicted
+var CountCacheSize = 1000
+
+// countCacheEntry cached count
+type countCacheEntry struct {
+	key     string
+	count   int64
+	expires time.Time
+}
+
+// countCache cache of CountCached mode shared by all boms, recently used entries are at front
+var countCache = struct {
+	sync.Mutex
+	entries map[string]*list.Element
+	order   *list.List
+}{entries: make(map[string]*list.Element), order: list.New()}
+
+// getCachedCount internal method for get count from cache, expired entry is evicted
+func getCachedCount(key string, now time.Time) (int64, bool) {
+	countCache.Lock()
+	defer countCache.Unlock()
+	item, ok := countCache.entries[key]
+	if !ok {
+		return 0, false
+	}
+	entry := item.Value.(*countCacheEntry)
+	if !now.Before(entry.expires) {
+		countCache.order.Remove(item)
+		delete(countCache.entries, key)
+		return 0, false
+	}
+	countCache.order.MoveToFront(item)
+	return entry.count, true
+}
+
+// setCachedCount internal method for put count to cache, least recently used entries over CountCacheSize are evicted
+func setCachedCount(key string, count int64, expires time.Time) {
+	countCache.Lock()
+	defer countCache.Unlock()
+	if item, ok := countCache.entries[key]; ok {
+		entry := item.Value.(*countCacheEntry)
+		entry.count, entry.expires = count, expires
+		countCache.order.MoveToFront(item)
+	} else {
+		countCache.entries[key] = countCache.order.PushFront(&countCacheEntry{key: key, count: count, expires: expires})
+	}
+	for countCache.order.Len() > CountCacheSize && countCache.order.Len() > 0 {
+		item := countCache.order.Back()
+		countCache.order.Remove(item)
+		delete(countCache.entries, item.Value.(*countCacheEntry).key)
+	}
+}
+
+// countQuery count query of count strategy
+type countQuery struct {
+	// estimated count is read from collection metadata, filter is not applied
+	estimated        bool
+	estimatedOptions []*options.EstimatedDocumentCountOptions
+	countOptions     []*options.CountOptions
+	// cap max count of CountCapped mode
+	cap int64
+}
+
+// countMode internal method for count mode of condition, estimated count of collection
+// can not be total of filtered list, so filtered list is not counted
+func (b *Bom) countMode(condition interface{}) CountMode {
+	mode := b.countStrategy.Mode
+	if mode == CountEstimated && !isEmptyCondition(condition) {
+		return CountSkipped
+	}
+	return mode
+}
+
+// buildCountQuery internal method for build count query of count strategy, nil is returned for CountSkipped mode
+func (b *Bom) buildCountQuery(condition interface{}) (*countQuery, error) {
+	mode := b.countMode(condition)
+	if mode == CountSkipped {
+		return nil, nil
+	}
+	if hasNearOperator(condition) {
+		return nil, ErrNearCount
+	}
+	switch {
+	case mode == CountEstimated:
+		return &countQuery{estimated: true, estimatedOptions: b.buildEstimatedCountOptions()}, nil
+	case mode == CountCapped && b.countStrategy.Cap > 0:
+		countOptions := options.Count().SetLimit(b.countStrategy.Cap)
+		return &countQuery{countOptions: b.buildCountOptions(countOptions), cap: b.countStrategy.Cap}, nil
+	case isEmptyCondition(condition):
+		// collection metadata is used for empty filter
+		return &countQuery{estimated: true, estimatedOptions: b.buildEstimatedCountOptions()}, nil
+	}
+	return &countQuery{countOptions: b.buildCountOptions()}, nil
+}
+
+// run count items by filter
+func (q *countQuery) run(ctx context.Context, collection *mongo.Collection, condition interface{}) (int64, error) {
+	if q.estimated {
+		return collection.EstimatedDocumentCount(ctx, q.estimatedOptions...)
+	}
+	return collection.CountDocuments(ctx, condition, q.countOptions...)
+}
+
+// command database command of count query, the same as driver sends
+func (q *countQuery) command(collection string, condition interface{}) primitive.D {
+	if q.estimated {
+		command := primitive.D{{Key: "count", Value: collection}}
+		if opts := options.MergeEstimatedDocumentCountOptions(q.estimatedOptions...); opts.MaxTime != nil {
+			command = append(command, (&Query{MaxTime: *opts.MaxTime}).commandOptions()...)
+		}
+		return command
+	}
+
+	opts := options.MergeCountOptions(q.countOptions...)
+	pipeline := primitive.A{primitive.D{{Key: MatchAggregateOperator, Value: condition}}}
+	if opts.Skip != nil {
+		pipeline = append(pipeline, primitive.D{{Key: "$skip", Value: *opts.Skip}})
+	}
+	if opts.Limit != nil {
+		pipeline = append(pipeline, primitive.D{{Key: "$limit", Value: *opts.Limit}})
+	}
+	pipeline = append(pipeline, primitive.D{{Key: "$group", Value: primitive.D{
+		{Key: "_id", Value: int32(1)},
+		{Key: "n", Value: primitive.D{{Key: "$sum", Value: int32(1)}}},
+	}}})
+
+	settings := &Query{Collation: opts.Collation, Hint: opts.Hint}
+	if opts.MaxTime != nil {
+		settings.MaxTime = *opts.MaxTime
+	}
+	command := primitive.D{
+		{Key: "aggregate", Value: collection},
+		{Key: "pipeline", Value: pipeline},
+		{Key: "cursor", Value: primitive.D{}},
+	}
+	return append(command, settings.commandOptions()...)
+}
+
+// countTotal internal method for count total items by count strategy,
+// capped is true when count reached cap of CountCapped mode
+func (b *Bom) countTotal(ctx context.Context, condition interface{}) (count int64, capped bool, err error) {
+	q, err := b.buildCountQuery(condition)
+	if err != nil || q == nil {
+		return 0, false, err
+	}
+	if b.countStrategy.Mode == CountCached {
+		count, err = b.cachedCount(ctx, condition, q)
+		return count, false, err
+	}
+	count, err = q.run(ctx, b.Mongo(), condition)
+	return count, q.cap > 0 && count >= q.cap, err
+}
+
+// cachedCount internal method for count which is cached by database, collection, filter, collation and hint
+func (b *Bom) cachedCount(ctx context.Context, condition interface{}, q *countQuery) (int64, error) {
+	key, err := b.countCacheKey()
+	if err != nil {
+		return 0, err
+	}
+
+	now := time.Now()
+	if count, ok := getCachedCount(key, now); ok {
+		return count, nil
+	}
+
+	count, err := q.run(ctx, b.Mongo(), condition)
+	if err != nil {
+		return 0, err
+	}
+	setCachedCount(key, count, now.Add(b.countStrategy.TTL))
+	return count, nil
+}
+
+// countCacheKey internal method for key of count cache, collation and hint change count of the same filter
+func (b *Bom) countCacheKey() (string, error) {
+	fingerprint, err := b.queryFingerprint(nil)
+	if err != nil {
+		return "", err
+	}
+	var collation interface{}
+	if b.collation != nil {
+		collation = b.collation.ToDocument()
+	}
+	settings, err := bson.Marshal(primitive.D{{Key: "collation", Value: collation}, {Key: "hint", Value: b.hint}})
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(settings)
+	return b.dbName + ":" + fingerprint + ":" + hex.EncodeToString(sum[:8]), nil
+}
+
+// runConcurrently internal method for run tasks in parallel, the first error cancels context of other tasks
+func runConcurrently(ctx context.Context, cancel context.CancelFunc, tasks ...func(ctx context.Context) error) error {
+	var wg sync.WaitGroup
+	var once sync.Once
+	var firstErr error
+	for _, task := range tasks {
+		wg.Add(1)
+		go func(task func(ctx context.Context) error) {
+			defer wg.Done()
+			if err := task(ctx); err != nil {
+				once.Do(func() {
+					firstErr = err
+					cancel()
+				})
+			}
+		}(task)
+	}
+	wg.Wait()
+	return firstErr
+}
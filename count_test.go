@@ -0,0 +1,123 @@
+package bom
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func TestRunConcurrently(t *testing.T) {
+	errCount := errors.New("count failed")
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	started := time.Now()
+	err := runConcurrently(ctx, cancel,
+		func(ctx context.Context) error {
+			return errCount
+		},
+		func(ctx context.Context) error {
+			// find waits until it is cancelled by failed count
+			<-ctx.Done()
+			return ctx.Err()
+		},
+	)
+	if !errors.Is(err, errCount) {
+		t.Errorf("runConcurrently() error = %v, want %v", err, errCount)
+	}
+	if elapsed := time.Since(started); elapsed >= time.Second {
+		t.Errorf("runConcurrently() did not cancel other task, elapsed %v", elapsed)
+	}
+}
+
+func TestCountMode_String(t *testing.T) {
+	if got := CappedCount(10000).Mode.String(); got != "capped" {
+		t.Errorf("String() = %v, want capped", got)
+	}
+	if got := CountMode(42).String(); got != "CountMode(42)" {
+		t.Errorf("String() = %v, want CountMode(42)", got)
+	}
+}
+
+func TestBom_countCacheKey(t *testing.T) {
+	key := func(b *Bom) string {
+		got, err := b.countCacheKey()
+		if err != nil {
+			t.Fatalf("countCacheKey() error = %v", err)
+		}
+		return got
+	}
+	newBom := func() *Bom {
+		b := &Bom{dbName: "db", dbCollection: "users"}
+		return b.WhereEq("name", "jo")
+	}
+
+	base := key(newBom())
+	if got := key(newBom()); got != base {
+		t.Errorf("countCacheKey() = %v, want %v", got, base)
+	}
+	collation := key(newBom().WithCollation(&options.Collation{Locale: "en", Strength: 2}))
+	hint := key(newBom().WithHint("name_1"))
+	for _, got := range []string{collation, hint} {
+		if got == base {
+			t.Errorf("countCacheKey() = %v, want other key than %v", got, base)
+		}
+	}
+	if collation == hint {
+		t.Errorf("countCacheKey() of collation and hint are equal: %v", collation)
+	}
+}
+
+func TestCountCache(t *testing.T) {
+	defer func(size int) { CountCacheSize = size }(CountCacheSize)
+	CountCacheSize = 2
+
+	now := time.Now()
+	setCachedCount("test:a", 1, now.Add(time.Minute))
+	setCachedCount("test:b", 2, now.Add(time.Minute))
+	// a becomes recently used, so b is evicted by c
+	if count, ok := getCachedCount("test:a", now); !ok || count != 1 {
+		t.Errorf("getCachedCount(a) = %v, %v, want 1, true", count, ok)
+	}
+	setCachedCount("test:c", 3, now.Add(time.Minute))
+	if _, ok := getCachedCount("test:b", now); ok {
+		t.Errorf("getCachedCount(b) is found, want evicted")
+	}
+	if count, ok := getCachedCount("test:c", now); !ok || count != 3 {
+		t.Errorf("getCachedCount(c) = %v, %v, want 3, true", count, ok)
+	}
+
+	// expired entry is evicted on read
+	if _, ok := getCachedCount("test:a", now.Add(time.Hour)); ok {
+		t.Errorf("getCachedCount(a) is found, want expired")
+	}
+	if n := countCache.order.Len(); n != 1 || len(countCache.entries) != 1 {
+		t.Errorf("count cache has %d entries, %d in map, want 1", n, len(countCache.entries))
+	}
+}
+
+func TestBom_countMode(t *testing.T) {
+	b := (&Bom{limit: &Limit{Page: 1, Size: 10}}).WithCountStrategy(EstimatedCount())
+	if got := b.countMode(b.getCondition()); got != CountEstimated {
+		t.Errorf("countMode() of unfiltered list = %v, want %v", got, CountEstimated)
+	}
+	if _, probe := b.listFindOptions(NewPagination(1, 10)); probe {
+		t.Errorf("listFindOptions() of unfiltered list probe = true, want false")
+	}
+
+	// filtered list is not counted, next page is detected by one extra item
+	b.WhereEq("status", "active")
+	if got := b.countMode(b.getCondition()); got != CountSkipped {
+		t.Errorf("countMode() of filtered list = %v, want %v", got, CountSkipped)
+	}
+	opts, probe := b.listFindOptions(NewPagination(1, 10))
+	if limit := options.MergeFindOptions(opts...).Limit; !probe || *limit != 11 {
+		t.Errorf("listFindOptions() of filtered list = %v, %v, want 11, true", *limit, probe)
+	}
+	if q, err := b.buildCountQuery(b.getCondition()); q != nil || err != nil {
+		t.Errorf("buildCountQuery() of filtered list = %v, %v, want nil, nil", q, err)
+	}
+}
@@ -0,0 +1,162 @@
+package bom
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"sort"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// cursorToken payload of opaque cursor token
+type cursorToken struct {
+	Fingerprint string        `bson:"f"`
+	Values      []interface{} `bson:"v"`
+	Before      bool          `bson:"b,omitempty"`
+}
+
+// WithCursor set opaque cursor token of keyset pagination (KeysetPage.NextCursor or KeysetPage.PrevCursor),
+// the token is rejected with ErrInvalidCursor when filter or sort of query are different
+func (b *Bom) WithCursor(token string) *Bom {
+	b.cursor = token
+	return b
+}
+
+// WithCursorKey set key for HMAC signature of cursor tokens, unsigned or forged tokens are rejected
+func (b *Bom) WithCursorKey(key []byte) *Bom {
+	b.cursorKey = key
+	return b
+}
+
+// ListWithCursor iteration method for deep pagination by opaque cursor tokens (see WithCursor),
+// returns tokens of the previous and the next page, empty string when there is no such page
+func (b *Bom) ListWithCursor(callback func(cursor *mongo.Cursor) error) (prev string, next string, err error) {
+	page, err := b.ListWithKeyset(callback)
+	if err != nil {
+		return "", "", err
+	}
+	return page.PrevCursor, page.NextCursor, nil
+}
+
+// encodeCursor internal method for encode sort key values, direction and query fingerprint to cursor token
+func (b *Bom) encodeCursor(sortDoc primitive.D, values []interface{}, before bool) (string, error) {
+	fingerprint, err := b.queryFingerprint(sortDoc)
+	if err != nil {
+		return "", err
+	}
+	payload, err := bson.Marshal(cursorToken{Fingerprint: fingerprint, Values: values, Before: before})
+	if err != nil {
+		return "", err
+	}
+	token := base64.RawURLEncoding.EncodeToString(payload)
+	if len(b.cursorKey) > 0 {
+		token += "." + base64.RawURLEncoding.EncodeToString(b.signCursor(payload))
+	}
+	return token, nil
+}
+
+// decodeCursor internal method for decode sort key values and direction of cursor token and check it belongs to query
+func (b *Bom) decodeCursor(sortDoc primitive.D, token string) (values []interface{}, before bool, err error) {
+	parts := strings.Split(token, ".")
+	if len(parts) > 2 {
+		return nil, false, ErrInvalidCursor
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return nil, false, ErrInvalidCursor
+	}
+	if len(b.cursorKey) > 0 {
+		if len(parts) != 2 {
+			return nil, false, ErrInvalidCursor
+		}
+		signature, err := base64.RawURLEncoding.DecodeString(parts[1])
+		if err != nil || !hmac.Equal(signature, b.signCursor(payload)) {
+			return nil, false, ErrInvalidCursor
+		}
+	}
+
+	var ct cursorToken
+	if err := bson.Unmarshal(payload, &ct); err != nil {
+		return nil, false, ErrInvalidCursor
+	}
+	fingerprint, err := b.queryFingerprint(sortDoc)
+	if err != nil {
+		return nil, false, err
+	}
+	if ct.Fingerprint != fingerprint || len(ct.Values) != len(sortDoc) {
+		return nil, false, ErrInvalidCursor
+	}
+	return ct.Values, ct.Before, nil
+}
+
+// signCursor internal method for HMAC-SHA256 signature of cursor payload
+func (b *Bom) signCursor(payload []byte) []byte {
+	mac := hmac.New(sha256.New, b.cursorKey)
+	mac.Write(payload)
+	return mac.Sum(nil)
+}
+
+// queryFingerprint internal method for short hash of collection, filter and sort,
+// bson keeps value types (int32 1 and int64 1 are different filters), maps are sorted by keys
+func (b *Bom) queryFingerprint(sortDoc primitive.D) (string, error) {
+	data, err := bson.Marshal(primitive.D{
+		{Key: "collection", Value: b.dbCollection},
+		{Key: "filter", Value: sortedMaps(b.getCondition())},
+		{Key: "sort", Value: sortDoc},
+	})
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:8]), nil
+}
+
+// sortedMaps internal method for replace maps of value with documents sorted by keys,
+// so the same value is always marshaled to the same bytes
+func sortedMaps(value interface{}) interface{} {
+	switch v := value.(type) {
+	case primitive.M:
+		return sortedDocument(v)
+	case map[string]interface{}:
+		return sortedDocument(v)
+	case primitive.D:
+		result := make(primitive.D, len(v))
+		for i, e := range v {
+			result[i] = primitive.E{Key: e.Key, Value: sortedMaps(e.Value)}
+		}
+		return result
+	case primitive.A:
+		return sortedArray(v)
+	case []interface{}:
+		return sortedArray(v)
+	}
+	return value
+}
+
+// sortedDocument internal method for document of map sorted by keys
+func sortedDocument(m map[string]interface{}) primitive.D {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	result := make(primitive.D, 0, len(m))
+	for _, key := range keys {
+		result = append(result, primitive.E{Key: key, Value: sortedMaps(m[key])})
+	}
+	return result
+}
+
+// sortedArray internal method for array with sorted maps
+func sortedArray(values []interface{}) primitive.A {
+	result := make(primitive.A, len(values))
+	for i, value := range values {
+		result[i] = sortedMaps(value)
+	}
+	return result
+}
@@ -0,0 +1,106 @@
+package bom
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestBom_Cursor(t *testing.T) {
+	created := time.Date(2020, 1, 2, 0, 0, 0, 0, time.UTC)
+	id := ToObj("5f8f1c3e2a1b3c4d5e6f7a8b")
+	newBom := func(key []byte) *Bom {
+		b := &Bom{dbCollection: "users", cursorKey: key}
+		return b.WhereEq("status", "active").WithSort(&Sort{Field: "createdAt", Type: "desc"})
+	}
+	encode := func(b *Bom, values ...interface{}) string {
+		sortDoc, _ := b.getKeysetSort()
+		token, err := b.encodeCursor(sortDoc, values, false)
+		if err != nil {
+			t.Fatalf("encodeCursor() error = %v", err)
+		}
+		return token
+	}
+	decode := func(b *Bom, token string) ([]interface{}, error) {
+		sortDoc, _ := b.getKeysetSort()
+		values, _, err := b.decodeCursor(sortDoc, token)
+		return values, err
+	}
+
+	token := encode(newBom(nil), created, id)
+	values, err := decode(newBom(nil), token)
+	if err != nil {
+		t.Fatalf("decodeCursor() error = %v", err)
+	}
+	want := []interface{}{primitive.NewDateTimeFromTime(created), id}
+	if !reflect.DeepEqual(values, want) {
+		t.Errorf("decodeCursor() = %v, want %v", values, want)
+	}
+
+	sortDoc, _ := newBom(nil).getKeysetSort()
+	prev, _ := newBom(nil).encodeCursor(sortDoc, []interface{}{created, id}, true)
+	if _, before, err := newBom(nil).decodeCursor(sortDoc, prev); err != nil || !before {
+		t.Errorf("decodeCursor() of previous page token = %v, %v, want before", before, err)
+	}
+
+	signed := encode(newBom([]byte("secret")), created, id)
+	forged := encode(newBom(nil), created.Add(time.Hour), id) + signed[strings.Index(signed, "."):]
+	if _, err := decode(newBom([]byte("secret")), signed); err != nil {
+		t.Errorf("decodeCursor() of signed token error = %v", err)
+	}
+
+	tests := []struct {
+		name  string
+		b     *Bom
+		token string
+	}{
+		{name: "different filter", b: newBom(nil).WhereEq("role", "admin"), token: token},
+		{name: "different sort", b: newBom(nil).WithSort(&Sort{Field: "name", Type: "asc"}), token: token},
+		{name: "different collection", b: newBom(nil).WithColl("admins"), token: token},
+		{name: "malformed", b: newBom(nil), token: "not a token"},
+		{name: "unsigned", b: newBom([]byte("secret")), token: token},
+		{name: "wrong key", b: newBom([]byte("other")), token: signed},
+		{name: "forged payload", b: newBom([]byte("secret")), token: forged},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := decode(tt.b, tt.token); !errors.Is(err, ErrInvalidCursor) {
+				t.Errorf("decodeCursor() error = %v, want %v", err, ErrInvalidCursor)
+			}
+		})
+	}
+}
+
+func TestBom_queryFingerprint(t *testing.T) {
+	sortDoc := primitive.D{{Key: "_id", Value: int32(1)}}
+	fingerprint := func(b *Bom) string {
+		got, err := b.queryFingerprint(sortDoc)
+		if err != nil {
+			t.Fatalf("queryFingerprint() error = %v", err)
+		}
+		return got
+	}
+
+	// filters differ only by numeric type
+	seen := make(map[string]interface{})
+	for _, value := range []interface{}{int32(1), int64(1), 1.0} {
+		got := fingerprint((&Bom{dbCollection: "users"}).WhereEq("age", value))
+		if other, ok := seen[got]; ok {
+			t.Errorf("queryFingerprint() of %T and %T are equal", value, other)
+		}
+		seen[got] = value
+	}
+
+	// maps are sorted, so key order does not change fingerprint
+	want := fingerprint((&Bom{dbCollection: "users"}).WhereEq("meta", primitive.M{"a": 1, "b": primitive.A{primitive.M{"c": 2, "d": 3}}}))
+	for i := 0; i < 10; i++ {
+		got := fingerprint((&Bom{dbCollection: "users"}).WhereEq("meta", primitive.M{"b": primitive.A{primitive.M{"d": 3, "c": 2}}, "a": 1}))
+		if got != want {
+			t.Fatalf("queryFingerprint() = %v, want %v", got, want)
+		}
+	}
+}
@@ -0,0 +1,326 @@
+package bom
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// identifierRegexp javascript identifier, used for keys and collection names in shell syntax
+var identifierRegexp = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*$`)
+
+// Query final query which is sent by ListWithPagination (find) or AggregateWithPagination (aggregate)
+type Query struct {
+	Database   string
+	Collection string
+
+	// find query
+	Filter     interface{}
+	Sort       interface{}
+	Projection interface{}
+	Skip       int64
+	Limit      int64
+
+	// aggregation pipeline, is set instead of find query when bom has pipeline
+	Pipeline []primitive.M
+
+	// Collation collation of find query or pipeline
+	Collation *options.Collation
+	// Hint index hint, MaxTime server side time limit and Comment profiler comment of query
+	Hint    interface{}
+	MaxTime time.Duration
+	Comment string
+	// Options other command options which are set by custom options example: batchSize
+	Options primitive.D
+}
+
+// ToQuery build final query of ListWithPagination (or AggregateWithPagination when pipeline is set),
+// custom options and one extra item of CountSkipped and CountCapped modes are included
+func (b *Bom) ToQuery() (*Query, error) {
+	q := &Query{
+		Database:   b.dbName,
+		Collection: b.dbCollection,
+	}
+	pagination := NewPagination(b.limit.Page, b.limit.Size)
+	if len(b.pipeline) > 0 {
+		stages := b.paginationStages(pagination)
+		pipeline, err := stages.Aggregate()
+		if err != nil {
+			return nil, err
+		}
+		q.Pipeline = pipeline
+
+		opts := options.MergeAggregateOptions(b.paginationAggregateOptions()...)
+		q.setOptions(opts.Collation, opts.Hint, opts.MaxTime, opts.Comment)
+		if opts.AllowDiskUse != nil {
+			q.Options = append(q.Options, primitive.E{Key: "allowDiskUse", Value: *opts.AllowDiskUse})
+		}
+		if opts.BypassDocumentValidation != nil {
+			q.Options = append(q.Options, primitive.E{Key: "bypassDocumentValidation", Value: *opts.BypassDocumentValidation})
+		}
+		return q, nil
+	}
+
+	findOptions, _ := b.listFindOptions(pagination)
+	opts := options.MergeFindOptions(findOptions...)
+	q.Filter = b.getCondition()
+	q.Sort = opts.Sort
+	q.Projection = opts.Projection
+	if opts.Skip != nil {
+		q.Skip = *opts.Skip
+	}
+	if opts.Limit != nil {
+		q.Limit = *opts.Limit
+	}
+	q.setOptions(opts.Collation, opts.Hint, opts.MaxTime, opts.Comment)
+	if opts.BatchSize != nil {
+		q.Options = append(q.Options, primitive.E{Key: "batchSize", Value: *opts.BatchSize})
+	}
+	if opts.Min != nil {
+		q.Options = append(q.Options, primitive.E{Key: "min", Value: opts.Min})
+	}
+	if opts.Max != nil {
+		q.Options = append(q.Options, primitive.E{Key: "max", Value: opts.Max})
+	}
+	if opts.AllowPartialResults != nil {
+		q.Options = append(q.Options, primitive.E{Key: "allowPartialResults", Value: *opts.AllowPartialResults})
+	}
+	if opts.NoCursorTimeout != nil {
+		q.Options = append(q.Options, primitive.E{Key: "noCursorTimeout", Value: *opts.NoCursorTimeout})
+	}
+	if opts.ReturnKey != nil {
+		q.Options = append(q.Options, primitive.E{Key: "returnKey", Value: *opts.ReturnKey})
+	}
+	if opts.ShowRecordID != nil {
+		q.Options = append(q.Options, primitive.E{Key: "showRecordId", Value: *opts.ShowRecordID})
+	}
+	return q, nil
+}
+
+// setOptions internal method for set collation, hint, max time and comment of merged options
+func (q *Query) setOptions(collation *options.Collation, hint interface{}, maxTime *time.Duration, comment *string) {
+	q.Collation, q.Hint = collation, hint
+	if maxTime != nil {
+		q.MaxTime = *maxTime
+	}
+	if comment != nil {
+		q.Comment = *comment
+	}
+}
+
+// IsAggregate query is aggregation pipeline
+func (q *Query) IsAggregate() bool {
+	return q.Pipeline != nil
+}
+
+// Command database command of query
+func (q *Query) Command() primitive.D {
+	if q.IsAggregate() {
+		command := primitive.D{
+			{Key: "aggregate", Value: q.Collection},
+			{Key: "pipeline", Value: q.Pipeline},
+			{Key: "cursor", Value: primitive.D{}},
+		}
+		return append(command, q.commandOptions()...)
+	}
+	command := primitive.D{
+		{Key: "find", Value: q.Collection},
+		{Key: "filter", Value: q.Filter},
+	}
+	if q.Sort != nil {
+		command = append(command, primitive.E{Key: "sort", Value: q.Sort})
+	}
+	if q.Projection != nil {
+		command = append(command, primitive.E{Key: "projection", Value: q.Projection})
+	}
+	if q.Skip > 0 {
+		command = append(command, primitive.E{Key: "skip", Value: q.Skip})
+	}
+	if q.Limit > 0 {
+		command = append(command, primitive.E{Key: "limit", Value: q.Limit})
+	}
+	return append(command, q.commandOptions()...)
+}
+
+// commandOptions collation, hint, maxTimeMS, comment and other options of query
+func (q *Query) commandOptions() primitive.D {
+	var opts primitive.D
+	if q.Collation != nil {
+		opts = append(opts, primitive.E{Key: "collation", Value: q.Collation.ToDocument()})
+	}
+	if q.Hint != nil {
+		opts = append(opts, primitive.E{Key: "hint", Value: q.Hint})
+	}
+	if q.MaxTime > 0 {
+		ms := q.MaxTime.Milliseconds()
+		var maxTimeMS interface{} = ms
+		if ms <= math.MaxInt32 {
+			// int32 keeps shell syntax short
+			maxTimeMS = int32(ms)
+		}
+		opts = append(opts, primitive.E{Key: "maxTimeMS", Value: maxTimeMS})
+	}
+	if q.Comment != "" {
+		opts = append(opts, primitive.E{Key: "comment", Value: q.Comment})
+	}
+	return append(opts, q.Options...)
+}
+
+// ExtJSON render database command of query as canonical extended json
+func (q *Query) ExtJSON() (string, error) {
+	data, err := bson.MarshalExtJSON(q.Command(), true, false)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// Shell render query in mongo shell syntax example: db.users.find({age: {$gt: 30}}).sort({name: 1}).limit(20)
+func (q *Query) Shell() (string, error) {
+	var sb strings.Builder
+	if identifierRegexp.MatchString(q.Collection) {
+		sb.WriteString("db." + q.Collection)
+	} else {
+		sb.WriteString(fmt.Sprintf("db.getCollection(%s)", quoteJS(q.Collection)))
+	}
+
+	if q.IsAggregate() {
+		pipeline, err := shellValue(q.Pipeline)
+		if err != nil {
+			return "", err
+		}
+		if opts := q.commandOptions(); opts != nil {
+			settings, err := shellValue(opts)
+			if err != nil {
+				return "", err
+			}
+			pipeline += ", " + settings
+		}
+		sb.WriteString(".aggregate(" + pipeline + ")")
+		return sb.String(), nil
+	}
+
+	filter, err := shellValue(q.Filter)
+	if err != nil {
+		return "", err
+	}
+	sb.WriteString(".find(" + filter)
+	if q.Projection != nil {
+		projection, err := shellValue(q.Projection)
+		if err != nil {
+			return "", err
+		}
+		sb.WriteString(", " + projection)
+	}
+	sb.WriteString(")")
+	for _, e := range q.commandOptions() {
+		value, err := shellValue(e.Value)
+		if err != nil {
+			return "", err
+		}
+		sb.WriteString("." + e.Key + "(" + value + ")")
+	}
+	if q.Sort != nil {
+		sort, err := shellValue(q.Sort)
+		if err != nil {
+			return "", err
+		}
+		sb.WriteString(".sort(" + sort + ")")
+	}
+	if q.Skip > 0 {
+		sb.WriteString(fmt.Sprintf(".skip(%d)", q.Skip))
+	}
+	if q.Limit > 0 {
+		sb.WriteString(fmt.Sprintf(".limit(%d)", q.Limit))
+	}
+	return sb.String(), nil
+}
+
+// String mongo shell representation of query
+func (q *Query) String() string {
+	s, err := q.Shell()
+	if err != nil {
+		return err.Error()
+	}
+	return s
+}
+
+// shellValue render value in mongo shell syntax
+func shellValue(value interface{}) (string, error) {
+	if value == nil {
+		return "{}", nil
+	}
+	data, err := bson.Marshal(primitive.D{{Key: "v", Value: value}})
+	if err != nil {
+		return "", err
+	}
+	return shellRawValue(bson.Raw(data).Lookup("v")), nil
+}
+
+// shellRawValue render bson value in mongo shell syntax
+func shellRawValue(v bson.RawValue) string {
+	switch v.Type {
+	case bsontype.EmbeddedDocument:
+		elements, _ := v.Document().Elements()
+		list := make([]string, 0, len(elements))
+		for _, e := range elements {
+			key := e.Key()
+			if !identifierRegexp.MatchString(key) {
+				key = quoteJS(key)
+			}
+			list = append(list, key+": "+shellRawValue(e.Value()))
+		}
+		return "{" + strings.Join(list, ", ") + "}"
+	case bsontype.Array:
+		values, _ := v.Array().Values()
+		list := make([]string, 0, len(values))
+		for _, item := range values {
+			list = append(list, shellRawValue(item))
+		}
+		return "[" + strings.Join(list, ", ") + "]"
+	case bsontype.String:
+		return quoteJS(v.StringValue())
+	case bsontype.Int32:
+		return strconv.FormatInt(int64(v.Int32()), 10)
+	case bsontype.Int64:
+		return fmt.Sprintf("NumberLong(%d)", v.Int64())
+	case bsontype.Double:
+		return strconv.FormatFloat(v.Double(), 'g', -1, 64)
+	case bsontype.Boolean:
+		return strconv.FormatBool(v.Boolean())
+	case bsontype.Null, bsontype.Undefined:
+		return "null"
+	case bsontype.ObjectID:
+		return fmt.Sprintf("ObjectId(%q)", v.ObjectID().Hex())
+	case bsontype.DateTime:
+		return fmt.Sprintf("ISODate(%q)", v.Time().UTC().Format(time.RFC3339Nano))
+	case bsontype.Regex:
+		pattern, flags := v.Regex()
+		return "/" + strings.Replace(pattern, "/", `\/`, -1) + "/" + flags
+	case bsontype.Decimal128:
+		return fmt.Sprintf("NumberDecimal(%q)", v.Decimal128().String())
+	case bsontype.Timestamp:
+		t, i := v.Timestamp()
+		return fmt.Sprintf("Timestamp(%d, %d)", t, i)
+	case bsontype.Binary:
+		subtype, data := v.Binary()
+		return fmt.Sprintf("BinData(%d, %q)", subtype, base64.StdEncoding.EncodeToString(data))
+	}
+	return v.String()
+}
+
+// quoteJS quote string as javascript literal
+func quoteJS(s string) string {
+	data, _ := json.Marshal(s)
+	return string(data)
+}
@@ -0,0 +1,82 @@
+package bom
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func TestBom_ToQuery(t *testing.T) {
+	created := time.Date(2020, 1, 2, 0, 0, 0, 0, time.UTC)
+	b := &Bom{dbName: "db", dbCollection: "users", limit: &Limit{Page: 2, Size: 10}}
+	b.WhereEq("_id", ToObj("5f8f1c3e2a1b3c4d5e6f7a8b")).WhereGt("created", created).WhereRegex("name", "^jo", "i").
+		WithSort(&Sort{Field: "name", Type: "desc"}).Select("name")
+
+	q, err := b.ToQuery()
+	if err != nil {
+		t.Fatalf("ToQuery() error = %v", err)
+	}
+
+	wantShell := `db.users.find({$and: [{_id: ObjectId("5f8f1c3e2a1b3c4d5e6f7a8b")}, {created: {$gt: ISODate("2020-01-02T00:00:00Z")}}, ` +
+		`{name: {$regex: /^jo/i}}]}, {name: 1}).sort({name: -1, _id: -1}).skip(10).limit(10)`
+	if got, err := q.Shell(); err != nil || got != wantShell {
+		t.Errorf("Shell() = %v, %v, want %v", got, err, wantShell)
+	}
+
+	wantJSON := `{"find":"users","filter":{"$and":[{"_id":{"$oid":"5f8f1c3e2a1b3c4d5e6f7a8b"}},` +
+		`{"created":{"$gt":{"$date":{"$numberLong":"1577923200000"}}}},{"name":{"$regex":{"$regularExpression":{"pattern":"^jo","options":"i"}}}}]},` +
+		`"sort":{"name":{"$numberInt":"-1"},"_id":{"$numberInt":"-1"}},"projection":{"name":{"$numberInt":"1"}},"skip":{"$numberLong":"10"},"limit":{"$numberLong":"10"}}`
+	if got, err := q.ExtJSON(); err != nil || got != wantJSON {
+		t.Errorf("ExtJSON() = %v, %v, want %v", got, err, wantJSON)
+	}
+}
+
+func TestBom_ToQuery_Aggregate(t *testing.T) {
+	b := &Bom{dbName: "db", dbCollection: "user-events", limit: &Limit{Page: 1, Size: 5}}
+	b.FillPipeline(NewProjectStage(primitive.M{"name": 1}))
+
+	q, err := b.ToQuery()
+	if err != nil {
+		t.Fatalf("ToQuery() error = %v", err)
+	}
+	// keys of $facet stage are not ordered, so only prefix is checked
+	want := `db.getCollection("user-events").aggregate([{$project: {name: 1}}, {$facet: {`
+	if got := q.String(); !strings.HasPrefix(got, want) {
+		t.Errorf("String() = %v, want prefix %v", got, want)
+	}
+}
+
+func TestBom_ToQuery_Collation(t *testing.T) {
+	b := &Bom{dbName: "db", dbCollection: "users", limit: &Limit{Page: 1, Size: 10}}
+	b.WhereEq("name", "jo").WithCollation(&options.Collation{Locale: "en", Strength: 2})
+
+	q, err := b.ToQuery()
+	if err != nil {
+		t.Fatalf("ToQuery() error = %v", err)
+	}
+	want := `db.users.find({name: "jo"}).collation({locale: "en", strength: 2}).limit(10)`
+	if got := q.String(); got != want {
+		t.Errorf("String() = %v, want %v", got, want)
+	}
+}
+
+func TestBom_ToQuery_Settings(t *testing.T) {
+	b := &Bom{dbName: "db", dbCollection: "users", limit: &Limit{Page: 1, Size: 10}}
+	b.WhereEq("name", "jo").WithHint("name_1").WithMaxTime(500 * time.Millisecond).WithComment("list users")
+
+	q, err := b.ToQuery()
+	if err != nil {
+		t.Fatalf("ToQuery() error = %v", err)
+	}
+	want := `db.users.find({name: "jo"}).hint("name_1").maxTimeMS(500).comment("list users").limit(10)`
+	if got := q.String(); got != want {
+		t.Errorf("String() = %v, want %v", got, want)
+	}
+	command := q.Command()
+	if got := command[len(command)-2]; got.Key != "maxTimeMS" || got.Value != int32(500) {
+		t.Errorf("Command() maxTimeMS = %v, want 500", got)
+	}
+}
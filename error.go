@@ -4,5 +4,11 @@ import "errors"
 
 // Define common errors
 var (
-	ErrClientRequired = errors.New("mongodb client is required")
+	ErrClientRequired  = errors.New("mongodb client is required")
+	ErrFieldNotAllowed = errors.New("field is not allowed")
+	ErrUnknownOperator = errors.New("unknown operator")
+	ErrInvalidValue    = errors.New("invalid value")
+	ErrInvalidCursor   = errors.New("invalid cursor")
+	ErrNearCount       = errors.New("$near and $nearSphere can not be counted, skip count or use $geoWithin")
+	ErrNoCurrent       = errors.New("iterator has no current item")
 )
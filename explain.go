@@ -0,0 +1,206 @@
+package bom
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Define explain verbosity modes
+const (
+	ExplainQueryPlanner      = "queryPlanner"
+	ExplainExecutionStats    = "executionStats"
+	ExplainAllPlansExecution = "allPlansExecution"
+)
+
+// ExplainResult parsed summary of explain command
+type ExplainResult struct {
+	// WinningPlan winning plan document
+	WinningPlan bson.Raw
+	// Stages stage names of winning plan from root to leaves example: [LIMIT FETCH IXSCAN]
+	Stages []string
+	// Indexes names of indexes used by winning plan
+	Indexes []string
+	// CollectionScan winning plan has COLLSCAN stage
+	CollectionScan bool
+
+	// execution stats, filled for executionStats and allPlansExecution verbosity
+	DocsExamined  int64
+	KeysExamined  int64
+	DocsReturned  int64
+	ExecutionTime time.Duration
+
+	// Raw source explain document
+	Raw bson.Raw
+}
+
+// Explain explain query which is issued by ListWithPagination
+// (or by AggregateWithPagination when pipeline is set) with verbosity example: bm.Explain(bom.ExplainExecutionStats)
+func (b *Bom) Explain(verbosity string) (*ExplainResult, error) {
+	command, err := b.explainCommand()
+	if err != nil {
+		return nil, err
+	}
+	return b.explain(command, verbosity)
+}
+
+// ExplainCount explain count query which is issued by ListWithPagination with count strategy,
+// nil is returned for CountSkipped mode (count query is not issued)
+func (b *Bom) ExplainCount(verbosity string) (*ExplainResult, error) {
+	command, err := b.explainCountCommand()
+	if err != nil || command == nil {
+		return nil, err
+	}
+	return b.explain(command, verbosity)
+}
+
+// explainCommand internal method for build command of ListWithPagination (AggregateWithPagination)
+func (b *Bom) explainCommand() (primitive.D, error) {
+	q, err := b.ToQuery()
+	if err != nil {
+		return nil, err
+	}
+	return q.Command(), nil
+}
+
+// explainCountCommand internal method for build count command of ListWithPagination
+func (b *Bom) explainCountCommand() (primitive.D, error) {
+	condition := b.getCondition()
+	q, err := b.buildCountQuery(condition)
+	if err != nil || q == nil {
+		return nil, err
+	}
+	return q.command(b.dbCollection, condition), nil
+}
+
+// explain internal method for run explain command with read preference and read concern of bom
+func (b *Bom) explain(command primitive.D, verbosity string) (*ExplainResult, error) {
+	if verbosity == "" {
+		verbosity = ExplainQueryPlanner
+	}
+
+	// set query context
+	ctx, cancel := b.getContext()
+	defer cancel()
+
+	// database inherits settings of client, which are not set on bom
+	dbOptions := options.Database()
+	if b.readPreference != nil {
+		dbOptions.SetReadPreference(b.readPreference)
+	}
+	if b.readConcern != nil {
+		dbOptions.SetReadConcern(b.readConcern)
+	}
+	db := b.client.Database(b.dbName, dbOptions)
+	raw, err := db.RunCommand(ctx, primitive.D{
+		{Key: "explain", Value: command},
+		{Key: "verbosity", Value: verbosity},
+	}, options.RunCmd().SetReadPreference(db.ReadPreference())).DecodeBytes()
+	if err != nil {
+		return nil, err
+	}
+	return parseExplain(raw), nil
+}
+
+// parseExplain parse explain document of find, count and aggregate commands
+func parseExplain(raw bson.Raw) *ExplainResult {
+	result := &ExplainResult{Raw: raw}
+	planner, stats := explainSections(raw)
+	if planner != nil {
+		if plan, ok := lookupDocument(planner, "winningPlan"); ok {
+			// slot based execution engine wraps plan with queryPlan
+			if queryPlan, ok := lookupDocument(plan, "queryPlan"); ok {
+				plan = queryPlan
+			}
+			result.WinningPlan = plan
+			result.walkPlan(plan)
+		}
+	}
+	if stats != nil {
+		result.DocsReturned = lookupInt64(stats, "nReturned")
+		result.DocsExamined = lookupInt64(stats, "totalDocsExamined")
+		result.KeysExamined = lookupInt64(stats, "totalKeysExamined")
+		result.ExecutionTime = time.Duration(lookupInt64(stats, "executionTimeMillis")) * time.Millisecond
+	}
+	return result
+}
+
+// explainSections find queryPlanner and executionStats sections,
+// aggregation explain has them in $cursor of the first stage
+func explainSections(raw bson.Raw) (planner bson.Raw, stats bson.Raw) {
+	if p, ok := lookupDocument(raw, "queryPlanner"); ok {
+		stats, _ = lookupDocument(raw, "executionStats")
+		return p, stats
+	}
+	if v, err := raw.LookupErr("stages"); err == nil && v.Type == bsontype.Array {
+		if first, err := v.Array().IndexErr(0); err == nil && first.Value().Type == bsontype.EmbeddedDocument {
+			if cursor, ok := lookupDocument(first.Value().Document(), "$cursor"); ok {
+				return explainSections(cursor)
+			}
+		}
+	}
+	return nil, nil
+}
+
+// walkPlan collect stages and indexes of plan
+func (r *ExplainResult) walkPlan(plan bson.Raw) {
+	if v, err := plan.LookupErr("stage"); err == nil && v.Type == bsontype.String {
+		r.Stages = append(r.Stages, v.StringValue())
+		if v.StringValue() == "COLLSCAN" {
+			r.CollectionScan = true
+		}
+	}
+	if v, err := plan.LookupErr("indexName"); err == nil && v.Type == bsontype.String {
+		r.addIndex(v.StringValue())
+	}
+	if input, ok := lookupDocument(plan, "inputStage"); ok {
+		r.walkPlan(input)
+	}
+	if v, err := plan.LookupErr("inputStages"); err == nil && v.Type == bsontype.Array {
+		values, _ := v.Array().Values()
+		for _, item := range values {
+			if item.Type == bsontype.EmbeddedDocument {
+				r.walkPlan(item.Document())
+			}
+		}
+	}
+}
+
+// addIndex add index name without duplicates
+func (r *ExplainResult) addIndex(name string) {
+	for _, index := range r.Indexes {
+		if index == name {
+			return
+		}
+	}
+	r.Indexes = append(r.Indexes, name)
+}
+
+// lookupDocument lookup embedded document by key
+func lookupDocument(raw bson.Raw, key string) (bson.Raw, bool) {
+	v, err := raw.LookupErr(key)
+	if err != nil || v.Type != bsontype.EmbeddedDocument {
+		return nil, false
+	}
+	return v.Document(), true
+}
+
+// lookupInt64 lookup number by key
+func lookupInt64(raw bson.Raw, key string) int64 {
+	v, err := raw.LookupErr(key)
+	if err != nil {
+		return 0
+	}
+	switch v.Type {
+	case bsontype.Int32:
+		return int64(v.Int32())
+	case bsontype.Int64:
+		return v.Int64()
+	case bsontype.Double:
+		return int64(v.Double())
+	}
+	return 0
+}
@@ -0,0 +1,160 @@
+package bom
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func TestParseExplain(t *testing.T) {
+	ixscan := primitive.D{{Key: "stage", Value: "FETCH"}, {Key: "inputStage", Value: primitive.D{
+		{Key: "stage", Value: "IXSCAN"}, {Key: "indexName", Value: "age_1"},
+	}}}
+	stats := primitive.D{
+		{Key: "nReturned", Value: int32(20)},
+		{Key: "executionTimeMillis", Value: int32(7)},
+		{Key: "totalKeysExamined", Value: int32(25)},
+		{Key: "totalDocsExamined", Value: int32(25)},
+	}
+	tests := []struct {
+		name        string
+		doc         primitive.D
+		wantStages  []string
+		wantIndexes []string
+		wantScan    bool
+		wantDocs    int64
+		wantTime    time.Duration
+	}{
+		{name: "find with index", doc: primitive.D{
+			{Key: "queryPlanner", Value: primitive.D{{Key: "winningPlan", Value: primitive.D{{Key: "stage", Value: "LIMIT"}, {Key: "inputStage", Value: ixscan}}}}},
+			{Key: "executionStats", Value: stats},
+		}, wantStages: []string{"LIMIT", "FETCH", "IXSCAN"}, wantIndexes: []string{"age_1"}, wantDocs: 25, wantTime: 7 * time.Millisecond},
+		{name: "aggregate collection scan", doc: primitive.D{
+			{Key: "stages", Value: primitive.A{primitive.D{{Key: "$cursor", Value: primitive.D{
+				{Key: "queryPlanner", Value: primitive.D{{Key: "winningPlan", Value: primitive.D{{Key: "stage", Value: "COLLSCAN"}}}}},
+			}}}}},
+		}, wantStages: []string{"COLLSCAN"}, wantScan: true},
+		{name: "or with index stages", doc: primitive.D{
+			{Key: "queryPlanner", Value: primitive.D{{Key: "winningPlan", Value: primitive.D{{Key: "queryPlan", Value: primitive.D{
+				{Key: "stage", Value: "OR"}, {Key: "inputStages", Value: primitive.A{ixscan, ixscan}},
+			}}}}}},
+		}, wantStages: []string{"OR", "FETCH", "IXSCAN", "FETCH", "IXSCAN"}, wantIndexes: []string{"age_1"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			raw, err := bson.Marshal(tt.doc)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := parseExplain(raw)
+			if !reflect.DeepEqual(got.Stages, tt.wantStages) {
+				t.Errorf("Stages = %v, want %v", got.Stages, tt.wantStages)
+			}
+			if !reflect.DeepEqual(got.Indexes, tt.wantIndexes) {
+				t.Errorf("Indexes = %v, want %v", got.Indexes, tt.wantIndexes)
+			}
+			if got.CollectionScan != tt.wantScan {
+				t.Errorf("CollectionScan = %v, want %v", got.CollectionScan, tt.wantScan)
+			}
+			if got.DocsExamined != tt.wantDocs || got.ExecutionTime != tt.wantTime {
+				t.Errorf("stats = %v, %v, want %v, %v", got.DocsExamined, got.ExecutionTime, tt.wantDocs, tt.wantTime)
+			}
+		})
+	}
+}
+
+func TestBom_explainCommand(t *testing.T) {
+	collation := &options.Collation{Locale: "en", Strength: 2}
+	b := &Bom{dbName: "db", dbCollection: "users", limit: &Limit{Page: 2, Size: 10}}
+	b.WhereEq("name", "jo").WithSort(&Sort{Field: "name", Type: "asc"}).WithCollation(collation).WithHint("name_1").
+		WithCountStrategy(SkippedCount()).SetFindOptions(options.Find().SetBatchSize(50).SetLimit(1000))
+
+	command, err := b.explainCommand()
+	if err != nil {
+		t.Fatalf("explainCommand() error = %v", err)
+	}
+	want := primitive.D{
+		{Key: "find", Value: "users"},
+		{Key: "filter", Value: primitive.D{{Key: "name", Value: "jo"}}},
+		{Key: "sort", Value: primitive.D{{Key: "name", Value: int32(1)}, {Key: "_id", Value: int32(1)}}},
+		{Key: "skip", Value: int64(10)},
+		{Key: "limit", Value: int64(11)},
+		{Key: "collation", Value: collation.ToDocument()},
+		{Key: "hint", Value: "name_1"},
+		{Key: "batchSize", Value: int32(50)},
+	}
+	if !reflect.DeepEqual(command, want) {
+		t.Errorf("explainCommand() = %v, want %v", command, want)
+	}
+
+	// explained command has the same options as find of ListWithPagination
+	opts, _ := b.listFindOptions(NewPagination(b.limit.Page, b.limit.Size))
+	find := options.MergeFindOptions(opts...)
+	if *find.Limit != 11 || *find.Skip != 10 || *find.BatchSize != 50 || find.Hint != "name_1" || find.Collation != collation {
+		t.Errorf("find options = %v, %v, %v, %v, %v", *find.Limit, *find.Skip, *find.BatchSize, find.Hint, find.Collation)
+	}
+}
+
+func TestBom_explainCountCommand(t *testing.T) {
+	collation := &options.Collation{Locale: "en"}
+	b := &Bom{dbName: "db", dbCollection: "users", limit: &Limit{Page: 1, Size: 10}}
+	b.WhereEq("name", "jo").WithCollation(collation).WithHint("name_1").WithMaxTime(time.Second)
+
+	group := primitive.D{{Key: "$group", Value: primitive.D{
+		{Key: "_id", Value: int32(1)},
+		{Key: "n", Value: primitive.D{{Key: "$sum", Value: int32(1)}}},
+	}}}
+	settings := primitive.D{
+		{Key: "collation", Value: collation.ToDocument()},
+		{Key: "hint", Value: "name_1"},
+		{Key: "maxTimeMS", Value: int32(1000)},
+	}
+	tests := []struct {
+		name     string
+		strategy CountStrategy
+		want     primitive.D
+	}{
+		{name: "exact", strategy: ExactCount(), want: append(primitive.D{
+			{Key: "aggregate", Value: "users"},
+			{Key: "pipeline", Value: primitive.A{primitive.D{{Key: MatchAggregateOperator, Value: b.getCondition()}}, group}},
+			{Key: "cursor", Value: primitive.D{}},
+		}, settings...)},
+		{name: "capped", strategy: CappedCount(1000), want: append(primitive.D{
+			{Key: "aggregate", Value: "users"},
+			{Key: "pipeline", Value: primitive.A{
+				primitive.D{{Key: MatchAggregateOperator, Value: b.getCondition()}},
+				primitive.D{{Key: "$limit", Value: int64(1000)}},
+				group,
+			}},
+			{Key: "cursor", Value: primitive.D{}},
+		}, settings...)},
+		// filtered list is not counted by estimated strategy
+		{name: "estimated", strategy: EstimatedCount(), want: nil},
+		{name: "skipped", strategy: SkippedCount(), want: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			command, err := b.WithCountStrategy(tt.strategy).explainCountCommand()
+			if err != nil || !reflect.DeepEqual(command, tt.want) {
+				t.Errorf("explainCountCommand() = %v, %v, want %v", command, err, tt.want)
+			}
+		})
+	}
+
+	unfiltered := &Bom{dbName: "db", dbCollection: "users", limit: &Limit{Page: 1, Size: 10}}
+	command, err := unfiltered.WithMaxTime(time.Second).WithCountStrategy(EstimatedCount()).explainCountCommand()
+	if want := (primitive.D{{Key: "count", Value: "users"}, {Key: "maxTimeMS", Value: int32(1000)}}); err != nil || !reflect.DeepEqual(command, want) {
+		t.Errorf("explainCountCommand() of unfiltered list = %v, %v, want %v", command, err, want)
+	}
+
+	// explained command has the same options as count of capped strategy
+	q, _ := b.WithCountStrategy(CappedCount(1000)).buildCountQuery(b.getCondition())
+	count := options.MergeCountOptions(q.countOptions...)
+	if *count.Limit != 1000 || count.Hint != "name_1" || count.Collation != collation || *count.MaxTime != time.Second {
+		t.Errorf("count options = %v, %v, %v, %v", *count.Limit, count.Hint, count.Collation, *count.MaxTime)
+	}
+}
@@ -0,0 +1,209 @@
+package bom
+
+import (
+	"fmt"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Expression typed aggregation expression, used in $expr conditions and pipeline stages
+type Expression interface {
+	// Build build aggregation expression value
+	Build() interface{}
+	// String human readable expression representation
+	String() string
+}
+
+type (
+	// FieldExpression document field path expression example: "$budget"
+	FieldExpression struct {
+		Field string
+	}
+
+	// LiteralExpression constant value expression
+	LiteralExpression struct {
+		Value interface{}
+	}
+
+	// OperatorExpression aggregation operator applied to arguments example: {$gt: ["$spent", "$budget"]}
+	OperatorExpression struct {
+		Operator  string
+		Arguments []Expression
+	}
+
+	// ExprFilter filter by aggregation expression ($expr)
+	ExprFilter struct {
+		Expression Expression
+	}
+)
+
+// expressionNames human readable names of infix expression operators
+var expressionNames = map[string]string{
+	EqualConditionOperator:          "=",
+	NotEqualConditionOperator:       "!=",
+	GreaterConditionOperator:        ">",
+	GreaterOrEqualConditionOperator: ">=",
+	LessConditionOperator:           "<",
+	LessOrEqualConditionOperator:    "<=",
+	AndConditionOperator:            "AND",
+	OrConditionOperator:             "OR",
+	"$add":                          "+",
+	"$subtract":                     "-",
+	"$multiply":                     "*",
+	"$divide":                       "/",
+}
+
+// ExprField field path expression example: bom.ExprField("budget")
+func ExprField(field string) *FieldExpression {
+	return &FieldExpression{Field: field}
+}
+
+// ExprValue constant value expression example: bom.ExprValue(100)
+func ExprValue(value interface{}) *LiteralExpression {
+	return &LiteralExpression{Value: value}
+}
+
+// ExprOperator custom aggregation operator expression, arguments which are not expressions are used as constants
+// example: bom.ExprOperator("$concat", bom.ExprField("first"), " ", bom.ExprField("last"))
+func ExprOperator(operator string, args ...interface{}) *OperatorExpression {
+	e := &OperatorExpression{Operator: operator, Arguments: make([]Expression, 0, len(args))}
+	for _, arg := range args {
+		if expr, ok := arg.(Expression); ok {
+			e.Arguments = append(e.Arguments, expr)
+		} else {
+			e.Arguments = append(e.Arguments, ExprValue(arg))
+		}
+	}
+	return e
+}
+
+// ExprEq equal expression example: bom.ExprEq(bom.ExprField("a"), bom.ExprField("b"))
+func ExprEq(a, b interface{}) *OperatorExpression {
+	return ExprOperator(EqualConditionOperator, a, b)
+}
+
+// ExprNe not equal expression
+func ExprNe(a, b interface{}) *OperatorExpression {
+	return ExprOperator(NotEqualConditionOperator, a, b)
+}
+
+// ExprGt greater expression example: bom.ExprGt(bom.ExprField("spent"), bom.ExprField("budget"))
+func ExprGt(a, b interface{}) *OperatorExpression {
+	return ExprOperator(GreaterConditionOperator, a, b)
+}
+
+// ExprGte greater or equal expression
+func ExprGte(a, b interface{}) *OperatorExpression {
+	return ExprOperator(GreaterOrEqualConditionOperator, a, b)
+}
+
+// ExprLt less expression
+func ExprLt(a, b interface{}) *OperatorExpression {
+	return ExprOperator(LessConditionOperator, a, b)
+}
+
+// ExprLte less or equal expression
+func ExprLte(a, b interface{}) *OperatorExpression {
+	return ExprOperator(LessOrEqualConditionOperator, a, b)
+}
+
+// ExprAnd logical and expression
+func ExprAnd(args ...interface{}) *OperatorExpression {
+	return ExprOperator(AndConditionOperator, args...)
+}
+
+// ExprOr logical or expression
+func ExprOr(args ...interface{}) *OperatorExpression {
+	return ExprOperator(OrConditionOperator, args...)
+}
+
+// ExprNot logical not expression
+func ExprNot(arg interface{}) *OperatorExpression {
+	return ExprOperator(NotConditionOperator, arg)
+}
+
+// ExprAdd sum expression example: bom.ExprAdd(bom.ExprField("price"), bom.ExprField("tax"))
+func ExprAdd(args ...interface{}) *OperatorExpression {
+	return ExprOperator("$add", args...)
+}
+
+// ExprSubtract subtract expression
+func ExprSubtract(a, b interface{}) *OperatorExpression {
+	return ExprOperator("$subtract", a, b)
+}
+
+// ExprMultiply multiply expression
+func ExprMultiply(args ...interface{}) *OperatorExpression {
+	return ExprOperator("$multiply", args...)
+}
+
+// ExprDivide divide expression
+func ExprDivide(a, b interface{}) *OperatorExpression {
+	return ExprOperator("$divide", a, b)
+}
+
+// Expr filter by aggregation expression example: bom.Expr(bom.ExprGt(bom.ExprField("spent"), bom.ExprField("budget")))
+func Expr(expression Expression) *ExprFilter {
+	return &ExprFilter{Expression: expression}
+}
+
+// Build build aggregation expression value
+func (e *FieldExpression) Build() interface{} {
+	return "$" + strings.TrimPrefix(e.Field, "$")
+}
+
+// String human readable expression representation
+func (e *FieldExpression) String() string {
+	return strings.TrimPrefix(e.Field, "$")
+}
+
+// Build build aggregation expression value, strings starting with $ are wrapped with $literal
+func (e *LiteralExpression) Build() interface{} {
+	if s, ok := e.Value.(string); ok && strings.HasPrefix(s, "$") {
+		return primitive.D{{Key: "$literal", Value: s}}
+	}
+	return e.Value
+}
+
+// String human readable expression representation
+func (e *LiteralExpression) String() string {
+	return formatValue(e.Value)
+}
+
+// Build build aggregation expression value
+func (e *OperatorExpression) Build() interface{} {
+	args := make(primitive.A, 0, len(e.Arguments))
+	for _, arg := range e.Arguments {
+		args = append(args, arg.Build())
+	}
+	return primitive.D{{Key: e.Operator, Value: args}}
+}
+
+// String human readable expression representation
+func (e *OperatorExpression) String() string {
+	args := make([]string, 0, len(e.Arguments))
+	for _, arg := range e.Arguments {
+		args = append(args, arg.String())
+	}
+	if name, ok := expressionNames[e.Operator]; ok && len(args) > 1 {
+		return "(" + strings.Join(args, " "+name+" ") + ")"
+	}
+	return fmt.Sprintf("%s(%s)", e.Operator, strings.Join(args, ", "))
+}
+
+// Compile build mongodb filter document
+func (f *ExprFilter) Compile() primitive.D {
+	if f.Expression == nil {
+		return primitive.D{}
+	}
+	return primitive.D{{Key: ExprConditionOperator, Value: f.Expression.Build()}}
+}
+
+// String human readable filter representation
+func (f *ExprFilter) String() string {
+	if f.Expression == nil {
+		return ""
+	}
+	return fmt.Sprintf("EXPR %s", f.Expression.String())
+}
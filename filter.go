@@ -0,0 +1,372 @@
+package bom
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Filter typed query condition, can be built without bom, combined and compiled to mongodb filter
+type Filter interface {
+	// Compile build mongodb filter document
+	Compile() primitive.D
+	// String human readable filter representation
+	String() string
+}
+
+type (
+	// FieldFilter condition on a single field example: {age: {$gt: 30}}
+	FieldFilter struct {
+		Field    string
+		Operator string
+		Value    interface{}
+	}
+
+	// LogicalFilter list of filters joined with logical operator ($and, $or, $nor)
+	LogicalFilter struct {
+		Operator string
+		Filters  []Filter
+	}
+
+	// NotFilter negation of filter
+	NotFilter struct {
+		Filter Filter
+	}
+
+	// TextFilter full text search filter ($text)
+	TextFilter struct {
+		Search             string
+		Language           string
+		CaseSensitive      bool
+		DiacriticSensitive bool
+	}
+
+	// TextOption full text search option
+	TextOption func(*TextFilter)
+)
+
+// operatorNames human readable names of operators
+var operatorNames = map[string]string{
+	EqualConditionOperator:          "=",
+	NotEqualConditionOperator:       "!=",
+	GreaterConditionOperator:        ">",
+	GreaterOrEqualConditionOperator: ">=",
+	LessConditionOperator:           "<",
+	LessOrEqualConditionOperator:    "<=",
+	InConditionOperator:             "IN",
+	NotInConditionOperator:          "NOT IN",
+	ExistsConditionOperator:         "EXISTS",
+	TypeConditionOperator:           "TYPE",
+	RegexConditionOperator:          "~",
+	AllConditionOperator:            "ALL",
+	SizeConditionOperator:           "SIZE",
+	ElMathConditionOperator:         "ELEMMATCH",
+	AndConditionOperator:            "AND",
+	OrConditionOperator:             "OR",
+	NorConditionOperator:            "NOR",
+}
+
+// Condition create field filter with custom operator example: bom.Condition("age", "$gt", 30)
+func Condition(field string, operator string, value interface{}) *FieldFilter {
+	return &FieldFilter{Field: field, Operator: operator, Value: value}
+}
+
+// Eq equal filter example: bom.Eq("_id", bom.ToObj(id))
+func Eq(field string, value interface{}) *FieldFilter {
+	return Condition(field, EqualConditionOperator, value)
+}
+
+// Ne not equal filter example: bom.Ne("status", "deleted")
+func Ne(field string, value interface{}) *FieldFilter {
+	return Condition(field, NotEqualConditionOperator, value)
+}
+
+// Gt greater filter example: bom.Gt("age", 30)
+func Gt(field string, value interface{}) *FieldFilter {
+	return Condition(field, GreaterConditionOperator, value)
+}
+
+// Gte greater or equal filter example: bom.Gte("age", 30)
+func Gte(field string, value interface{}) *FieldFilter {
+	return Condition(field, GreaterOrEqualConditionOperator, value)
+}
+
+// Lt less filter example: bom.Lt("age", 30)
+func Lt(field string, value interface{}) *FieldFilter {
+	return Condition(field, LessConditionOperator, value)
+}
+
+// Lte less or equal filter example: bom.Lte("age", 30)
+func Lte(field string, value interface{}) *FieldFilter {
+	return Condition(field, LessOrEqualConditionOperator, value)
+}
+
+// In filter example: bom.In("status", []string{"a", "b"})
+func In(field string, value interface{}) *FieldFilter {
+	return Condition(field, InConditionOperator, value)
+}
+
+// Nin not in filter example: bom.Nin("status", []string{"a", "b"})
+func Nin(field string, value interface{}) *FieldFilter {
+	return Condition(field, NotInConditionOperator, value)
+}
+
+// Exists field existence filter example: bom.Exists("deletedAt", false)
+func Exists(field string, exists bool) *FieldFilter {
+	return Condition(field, ExistsConditionOperator, exists)
+}
+
+// Type bson type filter example: bom.Type("age", bom.TypeInt, bom.TypeLong)
+func Type(field string, t BSONType, more ...BSONType) *FieldFilter {
+	if len(more) == 0 {
+		return Condition(field, TypeConditionOperator, t)
+	}
+	return Condition(field, TypeConditionOperator, append([]BSONType{t}, more...))
+}
+
+// All array contains all values filter example: bom.All("tags", []string{"a", "b"})
+func All(field string, values interface{}) *FieldFilter {
+	return Condition(field, AllConditionOperator, values)
+}
+
+// ArraySize array length filter example: bom.ArraySize("tags", 2)
+func ArraySize(field string, size int) *FieldFilter {
+	return Condition(field, SizeConditionOperator, size)
+}
+
+// ElementMatch array element matches sub filter example: bom.ElementMatch("items", bom.And(bom.Eq("sku", "a"), bom.Gt("qty", 1)))
+func ElementMatch(field string, filter Filter) *FieldFilter {
+	return Condition(field, ElMathConditionOperator, filter)
+}
+
+// Regex regular expression filter example: bom.Regex("name", "^jo", "i")
+func Regex(field string, pattern string, options string) *FieldFilter {
+	return Condition(field, RegexConditionOperator, primitive.Regex{Pattern: pattern, Options: options})
+}
+
+// Like filter by substring, value is escaped example: bom.Like("name", "jo", true)
+func Like(field string, value string, insensitive bool) *FieldFilter {
+	return Regex(field, regexp.QuoteMeta(value), regexOptions(insensitive))
+}
+
+// StartsWith filter by prefix, value is escaped example: bom.StartsWith("name", "jo", true)
+func StartsWith(field string, prefix string, insensitive bool) *FieldFilter {
+	return Regex(field, "^"+regexp.QuoteMeta(prefix), regexOptions(insensitive))
+}
+
+// Text full text search filter example: bom.Text("coffee shop", bom.TextLanguage("en"))
+func Text(search string, options ...TextOption) *TextFilter {
+	f := &TextFilter{Search: search}
+	for _, option := range options {
+		option(f)
+	}
+	return f
+}
+
+// TextLanguage set language of full text search
+func TextLanguage(language string) TextOption {
+	return func(f *TextFilter) {
+		f.Language = language
+	}
+}
+
+// TextCaseSensitive enable case sensitive full text search
+func TextCaseSensitive() TextOption {
+	return func(f *TextFilter) {
+		f.CaseSensitive = true
+	}
+}
+
+// TextDiacriticSensitive enable diacritic sensitive full text search
+func TextDiacriticSensitive() TextOption {
+	return func(f *TextFilter) {
+		f.DiacriticSensitive = true
+	}
+}
+
+// And join filters with $and example: bom.And(bom.Eq("a", 1), bom.Gt("b", 2))
+func And(filters ...Filter) *LogicalFilter {
+	return &LogicalFilter{Operator: AndConditionOperator, Filters: filters}
+}
+
+// Or join filters with $or example: bom.Or(bom.Eq("a", 1), bom.Eq("b", 2))
+func Or(filters ...Filter) *LogicalFilter {
+	return &LogicalFilter{Operator: OrConditionOperator, Filters: filters}
+}
+
+// Nor join filters with $nor example: bom.Nor(bom.Eq("a", 1), bom.Eq("b", 2))
+func Nor(filters ...Filter) *LogicalFilter {
+	return &LogicalFilter{Operator: NorConditionOperator, Filters: filters}
+}
+
+// Not negate filter example: bom.Not(bom.Gt("age", 30))
+func Not(filter Filter) *NotFilter {
+	return &NotFilter{Filter: filter}
+}
+
+// Compile build mongodb filter document
+func (f *FieldFilter) Compile() primitive.D {
+	value := f.Value
+	if sub, ok := value.(Filter); ok {
+		value = sub.Compile()
+	}
+	if f.Operator == EqualConditionOperator {
+		return primitive.D{{Key: f.Field, Value: value}}
+	}
+	return primitive.D{{Key: f.Field, Value: primitive.D{{Key: f.Operator, Value: value}}}}
+}
+
+// String human readable filter representation
+func (f *FieldFilter) String() string {
+	return fmt.Sprintf("%s %s %s", f.Field, operatorName(f.Operator), formatValue(f.Value))
+}
+
+// Compile build mongodb filter document, empty filters are skipped
+// and $and, $or with a single filter are reduced to that filter
+func (f *LogicalFilter) Compile() primitive.D {
+	var list primitive.A
+	for _, filter := range f.Filters {
+		if filter == nil {
+			continue
+		}
+		if d := filter.Compile(); len(d) > 0 {
+			list = append(list, d)
+		}
+	}
+	if len(list) == 0 {
+		return primitive.D{}
+	}
+	if len(list) == 1 && f.Operator != NorConditionOperator {
+		return list[0].(primitive.D)
+	}
+	return primitive.D{{Key: f.Operator, Value: list}}
+}
+
+// String human readable filter representation
+func (f *LogicalFilter) String() string {
+	var list []string
+	for _, filter := range f.Filters {
+		if filter == nil {
+			continue
+		}
+		if s := filter.String(); s != "" {
+			list = append(list, s)
+		}
+	}
+	if len(list) == 0 {
+		return ""
+	}
+	if f.Operator == NorConditionOperator {
+		return fmt.Sprintf("NOR (%s)", strings.Join(list, ", "))
+	}
+	if len(list) == 1 {
+		return list[0]
+	}
+	return "(" + strings.Join(list, " "+operatorName(f.Operator)+" ") + ")"
+}
+
+// Compile build mongodb filter document, field conditions are negated with $not (or $ne for equality),
+// other filters with $nor
+func (f *NotFilter) Compile() primitive.D {
+	if f.Filter == nil {
+		return primitive.D{}
+	}
+	if ff, ok := f.Filter.(*FieldFilter); ok {
+		if ff.Operator == EqualConditionOperator {
+			return Ne(ff.Field, ff.Value).Compile()
+		}
+		if re, ok := ff.Value.(primitive.Regex); ok && ff.Operator == RegexConditionOperator {
+			return primitive.D{{Key: ff.Field, Value: primitive.D{{Key: NotConditionOperator, Value: re}}}}
+		}
+		return primitive.D{{Key: ff.Field, Value: primitive.D{{Key: NotConditionOperator, Value: ff.Compile()[0].Value}}}}
+	}
+	d := f.Filter.Compile()
+	if len(d) == 0 {
+		return d
+	}
+	return primitive.D{{Key: NorConditionOperator, Value: primitive.A{d}}}
+}
+
+// String human readable filter representation
+func (f *NotFilter) String() string {
+	if f.Filter == nil {
+		return ""
+	}
+	return fmt.Sprintf("NOT (%s)", f.Filter.String())
+}
+
+// Compile build mongodb filter document
+func (f *TextFilter) Compile() primitive.D {
+	text := primitive.D{{Key: "$search", Value: f.Search}}
+	if f.Language != "" {
+		text = append(text, primitive.E{Key: "$language", Value: f.Language})
+	}
+	if f.CaseSensitive {
+		text = append(text, primitive.E{Key: "$caseSensitive", Value: true})
+	}
+	if f.DiacriticSensitive {
+		text = append(text, primitive.E{Key: "$diacriticSensitive", Value: true})
+	}
+	return primitive.D{{Key: TextConditionOperator, Value: text}}
+}
+
+// String human readable filter representation
+func (f *TextFilter) String() string {
+	return fmt.Sprintf("TEXT %q", f.Search)
+}
+
+// regexOptions get regular expression options
+func regexOptions(insensitive bool) string {
+	if insensitive {
+		return "i"
+	}
+	return ""
+}
+
+// isEmptyFilter check that filter matches all documents
+func isEmptyFilter(f Filter) bool {
+	return f == nil || len(f.Compile()) == 0
+}
+
+// operatorName get human readable operator name
+func operatorName(operator string) string {
+	if name, ok := operatorNames[operator]; ok {
+		return name
+	}
+	return operator
+}
+
+// formatValue human readable value representation
+func formatValue(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return "null"
+	case string:
+		return fmt.Sprintf("%q", v)
+	case BSONType:
+		return string(v)
+	case primitive.Regex:
+		return fmt.Sprintf("/%s/%s", v.Pattern, v.Options)
+	case primitive.ObjectID:
+		return fmt.Sprintf("ObjectId(%q)", v.Hex())
+	case time.Time:
+		return fmt.Sprintf("ISODate(%q)", v.UTC().Format(time.RFC3339Nano))
+	case Filter:
+		return "{" + v.String() + "}"
+	case primitive.D, primitive.M:
+		return fmt.Sprintf("%v", v)
+	}
+	rv := reflect.ValueOf(value)
+	if rv.Kind() == reflect.Slice || rv.Kind() == reflect.Array {
+		list := make([]string, rv.Len())
+		for i := 0; i < rv.Len(); i++ {
+			list[i] = formatValue(rv.Index(i).Interface())
+		}
+		return "(" + strings.Join(list, ", ") + ")"
+	}
+	return fmt.Sprintf("%v", value)
+}
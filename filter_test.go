@@ -0,0 +1,111 @@
+package bom
+
+import (
+	"reflect"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestFilter_Compile(t *testing.T) {
+	tests := []struct {
+		name   string
+		filter Filter
+		want   primitive.D
+	}{
+		{name: "eq", filter: Eq("name", "john"), want: primitive.D{{Key: "name", Value: "john"}}},
+		{name: "gt", filter: Gt("age", 30), want: primitive.D{{Key: "age", Value: primitive.D{{Key: "$gt", Value: 30}}}}},
+		{name: "and of one filter", filter: And(Eq("a", 1)), want: primitive.D{{Key: "a", Value: 1}}},
+		{name: "empty and", filter: And(), want: primitive.D{}},
+		{name: "or", filter: Or(Eq("a", 1), Lt("b", 2)), want: primitive.D{{Key: "$or", Value: primitive.A{
+			primitive.D{{Key: "a", Value: 1}},
+			primitive.D{{Key: "b", Value: primitive.D{{Key: "$lt", Value: 2}}}},
+		}}}},
+		{name: "nor of one filter", filter: Nor(Eq("a", 1)), want: primitive.D{{Key: "$nor", Value: primitive.A{
+			primitive.D{{Key: "a", Value: 1}},
+		}}}},
+		{name: "not eq", filter: Not(Eq("a", 1)), want: primitive.D{{Key: "a", Value: primitive.D{{Key: "$ne", Value: 1}}}}},
+		{name: "not gt", filter: Not(Gt("a", 1)), want: primitive.D{{Key: "a", Value: primitive.D{
+			{Key: "$not", Value: primitive.D{{Key: "$gt", Value: 1}}},
+		}}}},
+		{name: "like is escaped", filter: Like("name", "a.b(", true), want: primitive.D{{Key: "name", Value: primitive.D{
+			{Key: "$regex", Value: primitive.Regex{Pattern: `a\.b\(`, Options: "i"}},
+		}}}},
+		{name: "starts with", filter: StartsWith("name", "jo", false), want: primitive.D{{Key: "name", Value: primitive.D{
+			{Key: "$regex", Value: primitive.Regex{Pattern: "^jo"}},
+		}}}},
+		{name: "not regex", filter: Not(Regex("name", "^jo", "i")), want: primitive.D{{Key: "name", Value: primitive.D{
+			{Key: "$not", Value: primitive.Regex{Pattern: "^jo", Options: "i"}},
+		}}}},
+		{name: "text", filter: Text("coffee", TextLanguage("en"), TextDiacriticSensitive()), want: primitive.D{{Key: "$text", Value: primitive.D{
+			{Key: "$search", Value: "coffee"},
+			{Key: "$language", Value: "en"},
+			{Key: "$diacriticSensitive", Value: true},
+		}}}},
+		{name: "not of group", filter: Not(Or(Eq("a", 1), Eq("b", 2))), want: primitive.D{{Key: "$nor", Value: primitive.A{
+			primitive.D{{Key: "$or", Value: primitive.A{primitive.D{{Key: "a", Value: 1}}, primitive.D{{Key: "b", Value: 2}}}}},
+		}}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.filter.Compile(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Compile() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFilter_String(t *testing.T) {
+	tests := []struct {
+		name   string
+		filter Filter
+		want   string
+	}{
+		{name: "eq", filter: Eq("name", "john"), want: `name = "john"`},
+		{name: "in", filter: In("age", []int{1, 2}), want: `age IN (1, 2)`},
+		{name: "object id", filter: Eq("_id", ToObj("5f8f1c3e2a1b3c4d5e6f7a8b")), want: `_id = ObjectId("5f8f1c3e2a1b3c4d5e6f7a8b")`},
+		{name: "nested", filter: And(Gte("age", 30), Or(Eq("a", 1), Not(Eq("b", 2)))), want: `(age >= 30 AND (a = 1 OR NOT (b = 2)))`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.filter.String(); got != tt.want {
+				t.Errorf("String() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExprFilter_Compile(t *testing.T) {
+	tests := []struct {
+		name   string
+		filter Filter
+		want   primitive.D
+		str    string
+	}{
+		{name: "field to field", filter: Expr(ExprGt(ExprField("spent"), ExprField("budget"))),
+			want: primitive.D{{Key: "$expr", Value: primitive.D{{Key: "$gt", Value: primitive.A{"$spent", "$budget"}}}}},
+			str:  "EXPR (spent > budget)"},
+		{name: "computed", filter: Expr(ExprLt(ExprMultiply(ExprField("price"), 2), ExprField("limit"))),
+			want: primitive.D{{Key: "$expr", Value: primitive.D{{Key: "$lt", Value: primitive.A{
+				primitive.D{{Key: "$multiply", Value: primitive.A{"$price", 2}}},
+				"$limit",
+			}}}}},
+			str: "EXPR ((price * 2) < limit)"},
+		{name: "dollar literal", filter: Expr(ExprEq(ExprField("currency"), "$USD")),
+			want: primitive.D{{Key: "$expr", Value: primitive.D{{Key: "$eq", Value: primitive.A{
+				"$currency",
+				primitive.D{{Key: "$literal", Value: "$USD"}},
+			}}}}},
+			str: `EXPR (currency = "$USD")`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.filter.Compile(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Compile() = %v, want %v", got, tt.want)
+			}
+			if got := tt.filter.String(); got != tt.str {
+				t.Errorf("String() = %v, want %v", got, tt.str)
+			}
+		})
+	}
+}
@@ -0,0 +1,184 @@
+package bom
+
+import (
+	"encoding/json"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Define GeoJSON types
+const (
+	GeoJSONPoint        = "Point"
+	GeoJSONLineString   = "LineString"
+	GeoJSONPolygon      = "Polygon"
+	GeoJSONMultiPolygon = "MultiPolygon"
+)
+
+// Geometry GeoJSON geometry object
+type Geometry interface {
+	// GeoJSONType GeoJSON type name
+	GeoJSONType() string
+}
+
+type (
+	// Point GeoJSON point, coordinates are [longitude, latitude]
+	Point struct {
+		Coordinates []float64 `bson:"coordinates" json:"coordinates"`
+	}
+
+	// LineString GeoJSON line string
+	LineString struct {
+		Coordinates [][]float64 `bson:"coordinates" json:"coordinates"`
+	}
+
+	// Polygon GeoJSON polygon, first ring is exterior, others are holes, each ring must be closed
+	Polygon struct {
+		Coordinates [][][]float64 `bson:"coordinates" json:"coordinates"`
+	}
+
+	// MultiPolygon GeoJSON multi polygon
+	MultiPolygon struct {
+		Coordinates [][][][]float64 `bson:"coordinates" json:"coordinates"`
+	}
+
+	// geoJSON marshaling representation of geometry
+	geoJSON struct {
+		Type        string      `bson:"type" json:"type"`
+		Coordinates interface{} `bson:"coordinates" json:"coordinates"`
+	}
+)
+
+// NewPoint create GeoJSON point
+func NewPoint(longitude, latitude float64) Point {
+	return Point{Coordinates: []float64{longitude, latitude}}
+}
+
+// NewLineString create GeoJSON line string from [longitude, latitude] positions
+func NewLineString(positions ...[]float64) LineString {
+	return LineString{Coordinates: positions}
+}
+
+// NewPolygon create GeoJSON polygon from rings of [longitude, latitude] positions
+func NewPolygon(rings ...[][]float64) Polygon {
+	return Polygon{Coordinates: rings}
+}
+
+// NewMultiPolygon create GeoJSON multi polygon
+func NewMultiPolygon(polygons ...Polygon) MultiPolygon {
+	mp := MultiPolygon{Coordinates: make([][][][]float64, 0, len(polygons))}
+	for _, polygon := range polygons {
+		mp.Coordinates = append(mp.Coordinates, polygon.Coordinates)
+	}
+	return mp
+}
+
+// GeoJSONType GeoJSON type name
+func (p Point) GeoJSONType() string { return GeoJSONPoint }
+
+// GeoJSONType GeoJSON type name
+func (l LineString) GeoJSONType() string { return GeoJSONLineString }
+
+// GeoJSONType GeoJSON type name
+func (p Polygon) GeoJSONType() string { return GeoJSONPolygon }
+
+// GeoJSONType GeoJSON type name
+func (mp MultiPolygon) GeoJSONType() string { return GeoJSONMultiPolygon }
+
+// MarshalBSON marshal point with GeoJSON type
+func (p Point) MarshalBSON() ([]byte, error) {
+	return bson.Marshal(geoJSON{Type: p.GeoJSONType(), Coordinates: p.Coordinates})
+}
+
+// MarshalBSON marshal line string with GeoJSON type
+func (l LineString) MarshalBSON() ([]byte, error) {
+	return bson.Marshal(geoJSON{Type: l.GeoJSONType(), Coordinates: l.Coordinates})
+}
+
+// MarshalBSON marshal polygon with GeoJSON type
+func (p Polygon) MarshalBSON() ([]byte, error) {
+	return bson.Marshal(geoJSON{Type: p.GeoJSONType(), Coordinates: p.Coordinates})
+}
+
+// MarshalBSON marshal multi polygon with GeoJSON type
+func (mp MultiPolygon) MarshalBSON() ([]byte, error) {
+	return bson.Marshal(geoJSON{Type: mp.GeoJSONType(), Coordinates: mp.Coordinates})
+}
+
+// MarshalJSON marshal point with GeoJSON type
+func (p Point) MarshalJSON() ([]byte, error) {
+	return json.Marshal(geoJSON{Type: p.GeoJSONType(), Coordinates: p.Coordinates})
+}
+
+// MarshalJSON marshal line string with GeoJSON type
+func (l LineString) MarshalJSON() ([]byte, error) {
+	return json.Marshal(geoJSON{Type: l.GeoJSONType(), Coordinates: l.Coordinates})
+}
+
+// MarshalJSON marshal polygon with GeoJSON type
+func (p Polygon) MarshalJSON() ([]byte, error) {
+	return json.Marshal(geoJSON{Type: p.GeoJSONType(), Coordinates: p.Coordinates})
+}
+
+// MarshalJSON marshal multi polygon with GeoJSON type
+func (mp MultiPolygon) MarshalJSON() ([]byte, error) {
+	return json.Marshal(geoJSON{Type: mp.GeoJSONType(), Coordinates: mp.Coordinates})
+}
+
+// Near documents near point ordered by distance filter, maxDistance in meters (0 is unlimited)
+// example: bom.Near("location", bom.NewPoint(37.61, 55.75), 1000),
+// $near can not be counted, so ListWithPagination returns ErrNearCount unless count is skipped or estimated
+// (use GeoWithin or GeoNearStage for counted pages)
+func Near(field string, point Point, maxDistance float64) *FieldFilter {
+	return Condition(field, NearConditionOperator, nearValue(point, maxDistance))
+}
+
+// NearSphere documents near point on sphere ordered by distance filter, maxDistance in meters (0 is unlimited),
+// it can not be counted as well as Near
+func NearSphere(field string, point Point, maxDistance float64) *FieldFilter {
+	return Condition(field, NearSphereConditionOperator, nearValue(point, maxDistance))
+}
+
+// GeoWithin geometry within filter example: bom.GeoWithin("location", bom.NewPolygon(ring))
+func GeoWithin(field string, geometry Geometry) *FieldFilter {
+	return Condition(field, GeoWithinConditionOperator, primitive.D{{Key: GeometryOperator, Value: geometry}})
+}
+
+// GeoIntersects geometry intersects filter example: bom.GeoIntersects("area", bom.NewPoint(37.61, 55.75))
+func GeoIntersects(field string, geometry Geometry) *FieldFilter {
+	return Condition(field, GeoIntersectsConditionOperator, primitive.D{{Key: GeometryOperator, Value: geometry}})
+}
+
+// nearValue internal method for build $near value
+func nearValue(point Point, maxDistance float64) primitive.D {
+	value := primitive.D{{Key: GeometryOperator, Value: point}}
+	if maxDistance > 0 {
+		value = append(value, primitive.E{Key: "$maxDistance", Value: maxDistance})
+	}
+	return value
+}
+
+// hasNearOperator internal method for check condition contains $near or $nearSphere
+func hasNearOperator(value interface{}) bool {
+	switch v := value.(type) {
+	case primitive.D:
+		for _, e := range v {
+			if e.Key == NearConditionOperator || e.Key == NearSphereConditionOperator || hasNearOperator(e.Value) {
+				return true
+			}
+		}
+	case primitive.M:
+		for key, item := range v {
+			if key == NearConditionOperator || key == NearSphereConditionOperator || hasNearOperator(item) {
+				return true
+			}
+		}
+	case primitive.A:
+		for _, item := range v {
+			if hasNearOperator(item) {
+				return true
+			}
+		}
+	}
+	return false
+}
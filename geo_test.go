@@ -0,0 +1,140 @@
+package bom
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestGeometry_Marshal(t *testing.T) {
+	ring := [][]float64{{0, 0}, {0, 1}, {1, 1}, {0, 0}}
+	tests := []struct {
+		name     string
+		geometry Geometry
+		wantType string
+		wantJSON string
+	}{
+		{name: "point", geometry: NewPoint(37.61, 55.75), wantType: GeoJSONPoint,
+			wantJSON: `{"type":"Point","coordinates":[37.61,55.75]}`},
+		{name: "line string", geometry: NewLineString([]float64{0, 0}, []float64{1, 1}), wantType: GeoJSONLineString,
+			wantJSON: `{"type":"LineString","coordinates":[[0,0],[1,1]]}`},
+		{name: "polygon", geometry: NewPolygon(ring), wantType: GeoJSONPolygon,
+			wantJSON: `{"type":"Polygon","coordinates":[[[0,0],[0,1],[1,1],[0,0]]]}`},
+		{name: "multi polygon", geometry: NewMultiPolygon(NewPolygon(ring)), wantType: GeoJSONMultiPolygon,
+			wantJSON: `{"type":"MultiPolygon","coordinates":[[[[0,0],[0,1],[1,1],[0,0]]]]}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := bson.Marshal(primitive.D{{Key: "geometry", Value: tt.geometry}})
+			if err != nil {
+				t.Fatalf("bson.Marshal() error = %v", err)
+			}
+			if got := bson.Raw(data).Lookup("geometry", "type").StringValue(); got != tt.wantType {
+				t.Errorf("bson type = %v, want %v", got, tt.wantType)
+			}
+			js, err := json.Marshal(tt.geometry)
+			if err != nil {
+				t.Fatalf("json.Marshal() error = %v", err)
+			}
+			if string(js) != tt.wantJSON {
+				t.Errorf("json = %s, want %s", js, tt.wantJSON)
+			}
+		})
+	}
+}
+
+func TestPoint_UnmarshalBSON(t *testing.T) {
+	data, err := bson.Marshal(NewPoint(37.61, 55.75))
+	if err != nil {
+		t.Fatalf("bson.Marshal() error = %v", err)
+	}
+	var got Point
+	if err := bson.Unmarshal(data, &got); err != nil {
+		t.Fatalf("bson.Unmarshal() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, NewPoint(37.61, 55.75)) {
+		t.Errorf("bson.Unmarshal() = %v, want %v", got, NewPoint(37.61, 55.75))
+	}
+}
+
+func TestBom_WhereNear(t *testing.T) {
+	b := &Bom{limit: &Limit{Page: 1, Size: DefaultSize}}
+	b.WhereNear("location", NewPoint(37.61, 55.75), 1000)
+	want := primitive.D{{Key: "location", Value: primitive.D{{Key: "$near", Value: primitive.D{
+		{Key: "$geometry", Value: NewPoint(37.61, 55.75)},
+		{Key: "$maxDistance", Value: float64(1000)},
+	}}}}}
+	if got := b.getCondition(); !reflect.DeepEqual(got, want) {
+		t.Errorf("getCondition() = %v, want %v", got, want)
+	}
+}
+
+func TestGeoFilters_Compile(t *testing.T) {
+	ring := [][]float64{{0, 0}, {0, 1}, {1, 1}, {0, 0}}
+	tests := []struct {
+		name   string
+		filter Filter
+		want   primitive.D
+	}{
+		{name: "geo within", filter: GeoWithin("location", NewPolygon(ring)),
+			want: primitive.D{{Key: "location", Value: primitive.D{{Key: "$geoWithin", Value: primitive.D{
+				{Key: "$geometry", Value: NewPolygon(ring)},
+			}}}}}},
+		{name: "geo intersects", filter: GeoIntersects("area", NewPoint(37.61, 55.75)),
+			want: primitive.D{{Key: "area", Value: primitive.D{{Key: "$geoIntersects", Value: primitive.D{
+				{Key: "$geometry", Value: NewPoint(37.61, 55.75)},
+			}}}}}},
+		{name: "near sphere without max distance", filter: NearSphere("location", NewPoint(1, 2), 0),
+			want: primitive.D{{Key: "location", Value: primitive.D{{Key: "$nearSphere", Value: primitive.D{
+				{Key: "$geometry", Value: NewPoint(1, 2)},
+			}}}}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.filter.Compile(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Compile() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGeoNearStage_GetStage(t *testing.T) {
+	stage := NewGeoNearStage(NewPoint(37.61, 55.75), "distance").
+		SetMaxDistance(1000).SetMinDistance(10).SetKey("location").SetQuery(Eq("type", "cafe"))
+	want := primitive.M{"$geoNear": primitive.M{
+		"near":          NewPoint(37.61, 55.75),
+		"distanceField": "distance",
+		"spherical":     true,
+		"maxDistance":   float64(1000),
+		"minDistance":   float64(10),
+		"key":           "location",
+		"query":         primitive.D{{Key: "type", Value: "cafe"}},
+	}}
+	if got := stage.GetStage(); !reflect.DeepEqual(got, want) {
+		t.Errorf("GetStage() = %v, want %v", got, want)
+	}
+
+	want = primitive.M{"$geoNear": primitive.M{"near": NewPoint(1, 2), "distanceField": "d", "spherical": false}}
+	if got := NewGeoNearStage(NewPoint(1, 2), "d").SetSpherical(false).GetStage(); !reflect.DeepEqual(got, want) {
+		t.Errorf("GetStage() = %v, want %v", got, want)
+	}
+}
+
+func TestBom_countTotal_Near(t *testing.T) {
+	b := &Bom{limit: &Limit{Page: 1, Size: DefaultSize}}
+	b.WhereEq("type", "cafe").WhereNear("location", NewPoint(37.61, 55.75), 1000)
+	for _, strategy := range []CountStrategy{ExactCount(), CappedCount(100), CachedCount(time.Minute)} {
+		if _, _, err := b.WithCountStrategy(strategy).countTotal(context.Background(), b.getCondition()); !errors.Is(err, ErrNearCount) {
+			t.Errorf("countTotal() with %v count error = %v, want %v", strategy.Mode, err, ErrNearCount)
+		}
+	}
+	if count, _, err := b.WithCountStrategy(SkippedCount()).countTotal(context.Background(), b.getCondition()); err != nil || count != 0 {
+		t.Errorf("countTotal() with skipped count = %v, %v, want 0, nil", count, err)
+	}
+}
@@ -0,0 +1,108 @@
+package bom
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Iterator pull based iterator over query results, the query is run by the first Next
+type Iterator struct {
+	// copy of bom, so later changes of source bom do not affect iterator
+	b      *Bom
+	cursor *mongo.Cursor
+	err    error
+	closed bool
+}
+
+// Iter create iterator over items with filter, sort and projection of bom, page and size are applied
+// only when one of them is set by WithSize or WithLimit (page without size has default size)
+// example: it := bm.Iter(); defer it.Close(); for it.Next(ctx) { it.Decode(&user) }; err := it.Err()
+func (b *Bom) Iter() *Iterator {
+	return &Iterator{b: b.Clone()}
+}
+
+// Next move to the next item, false is returned when items are over or on error (see Err),
+// ctx controls the iteration, query timeout of bom is applied only to the query run by the first Next
+func (it *Iterator) Next(ctx context.Context) bool {
+	if it.err != nil || it.closed {
+		return false
+	}
+	if it.cursor == nil {
+		if err := it.find(ctx); err != nil {
+			it.err = err
+			return false
+		}
+	}
+	if it.cursor.Next(ctx) {
+		return true
+	}
+	it.err = it.cursor.Err()
+	return false
+}
+
+// find internal method for run query of iterator with query timeout of bom
+func (it *Iterator) find(ctx context.Context) error {
+	b := it.b
+	if b.queryTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, b.queryTimeout)
+		defer cancel()
+	}
+	cur, err := b.Mongo().Find(ctx, b.getCondition(), b.buildFindOptions(it.findOptions())...)
+	if err != nil {
+		return err
+	}
+	it.cursor = cur
+	return nil
+}
+
+// findOptions internal method for build find options of iterator, all items are iterated unless limit is set
+func (it *Iterator) findOptions() *options.FindOptions {
+	b := it.b
+	findOptions := b.paginationFindOptions(NewPagination(b.limit.Page, b.limit.Size))
+	if !b.limitSet {
+		findOptions.Limit, findOptions.Skip = nil, nil
+	}
+	return findOptions
+}
+
+// Decode decode current item into v
+func (it *Iterator) Decode(v interface{}) error {
+	if it.cursor == nil {
+		return ErrNoCurrent
+	}
+	return it.cursor.Decode(v)
+}
+
+// Current raw current item, nil before the first Next
+func (it *Iterator) Current() bson.Raw {
+	if it.cursor == nil {
+		return nil
+	}
+	return it.cursor.Current
+}
+
+// Err error of query or iteration
+func (it *Iterator) Err() error {
+	return it.err
+}
+
+// Close close cursor of iterator, it is safe to call it several times
+func (it *Iterator) Close() error {
+	if it.closed {
+		return nil
+	}
+	it.closed = true
+	if it.cursor == nil {
+		return nil
+	}
+
+	// set query context
+	ctx, cancel := it.b.getContext()
+	defer cancel()
+
+	return it.cursor.Close(ctx)
+}
@@ -0,0 +1,136 @@
+package bom
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func TestIterator_NotStarted(t *testing.T) {
+	b := &Bom{limit: &Limit{Page: 1, Size: DefaultSize}}
+	it := b.WhereEq("status", "active").Iter()
+
+	// iterator works on a copy of bom
+	b.WhereEq("role", "admin")
+	if got := len(it.b.conditions.whereConditions); got != 1 {
+		t.Errorf("Iter() conditions = %v, want 1", got)
+	}
+
+	var v struct{}
+	if err := it.Decode(&v); !errors.Is(err, ErrNoCurrent) {
+		t.Errorf("Decode() error = %v, want %v", err, ErrNoCurrent)
+	}
+	if it.Current() != nil {
+		t.Errorf("Current() = %v, want nil", it.Current())
+	}
+	if err := it.Close(); err != nil {
+		t.Errorf("Close() error = %v", err)
+	}
+	if it.Next(context.Background()) {
+		t.Errorf("Next() after Close() = true, want false")
+	}
+	if err := it.Err(); err != nil {
+		t.Errorf("Err() = %v, want nil", err)
+	}
+}
+
+func TestIterator_findOptions(t *testing.T) {
+	b := &Bom{limit: &Limit{Page: 2, Size: DefaultSize}}
+	b.WithSort(&Sort{Field: "name", Type: "asc"})
+
+	// page and size of bom are defaults, so all items are iterated
+	got := b.Iter().findOptions()
+	if got.Limit != nil || got.Skip != nil || got.Sort == nil {
+		t.Errorf("findOptions() limit = %v, skip = %v, sort = %v, want nil, nil, sort", got.Limit, got.Skip, got.Sort)
+	}
+
+	got = b.WithLimit(&Limit{Page: 3}).Iter().findOptions()
+	if got.Limit == nil || *got.Limit != DefaultSize || got.Skip == nil || *got.Skip != 2*DefaultSize {
+		t.Errorf("findOptions() limit = %v, skip = %v, want %v, %v", got.Limit, got.Skip, DefaultSize, 2*DefaultSize)
+	}
+
+	b.WithLimit(&Limit{Page: 2})
+	got = b.WithSize(10).Iter().findOptions()
+	if got.Limit == nil || *got.Limit != 10 || got.Skip == nil || *got.Skip != 10 {
+		t.Errorf("findOptions() limit = %v, skip = %v, want 10, 10", got.Limit, got.Skip)
+	}
+}
+
+func TestIterator_QueryError(t *testing.T) {
+	client := newUnreachableClient(t, 30*time.Second)
+	defer client.Disconnect(context.Background())
+
+	b := &Bom{client: client, dbName: "db", dbCollection: "users", queryTimeout: time.Minute, limit: &Limit{Page: 1, Size: DefaultSize}}
+	it := b.Iter()
+
+	// query is controlled by context of caller
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	var v struct{}
+	if err := it.Decode(&v); !errors.Is(err, ErrNoCurrent) {
+		t.Errorf("Decode() before Next() error = %v, want %v", err, ErrNoCurrent)
+	}
+	if it.Next(ctx) {
+		t.Fatalf("Next() = true, want false")
+	}
+	queryErr := it.Err()
+	if queryErr == nil {
+		t.Fatalf("Err() = nil, want query error")
+	}
+	if err := it.Decode(&v); !errors.Is(err, ErrNoCurrent) {
+		t.Errorf("Decode() after failed Next() error = %v, want %v", err, ErrNoCurrent)
+	}
+	if it.Next(context.Background()) {
+		t.Errorf("Next() after error = true, want false")
+	}
+
+	for i := 0; i < 2; i++ {
+		if err := it.Close(); err != nil {
+			t.Errorf("Close() #%d error = %v", i+1, err)
+		}
+	}
+	if it.Next(context.Background()) {
+		t.Errorf("Next() after Close() = true, want false")
+	}
+	if err := it.Err(); err != queryErr {
+		t.Errorf("Err() after Close() = %v, want %v", err, queryErr)
+	}
+}
+
+func TestIterator_QueryTimeout(t *testing.T) {
+	client := newUnreachableClient(t, 30*time.Second)
+	defer client.Disconnect(context.Background())
+
+	// query timeout of bom limits the query, caller context has no deadline
+	b := &Bom{client: client, dbName: "db", dbCollection: "users", queryTimeout: 50 * time.Millisecond, limit: &Limit{Page: 1, Size: DefaultSize}}
+	it := b.Iter()
+	defer it.Close()
+
+	start := time.Now()
+	if it.Next(context.Background()) {
+		t.Fatalf("Next() = true, want false")
+	}
+	if it.Err() == nil {
+		t.Errorf("Err() = nil, want query error")
+	}
+	if elapsed := time.Since(start); elapsed > 10*time.Second {
+		t.Errorf("Next() took %v, want query timeout", elapsed)
+	}
+}
+
+// newUnreachableClient client of server which does not exist, queries fail by server selection
+func newUnreachableClient(t *testing.T, selectionTimeout time.Duration) *mongo.Client {
+	client, err := mongo.NewClient(options.Client().ApplyURI("mongodb://127.0.0.1:1").SetServerSelectionTimeout(selectionTimeout))
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	if err := client.Connect(context.Background()); err != nil {
+		t.Fatalf("Connect() error = %v", err)
+	}
+	return client
+}
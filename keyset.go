@@ -0,0 +1,358 @@
+package bom
+
+import (
+	"fmt"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// KeysetPage result of keyset (seek) pagination
+type KeysetPage struct {
+	// Keys sort keys of query, _id tie breaker is the last key
+	Keys []string
+	// Prev sort key values of the first item, position of the previous page (see WithBefore)
+	Prev []interface{}
+	// Next sort key values of the last item, position of the next page (see WithAfter)
+	Next []interface{}
+	// PrevCursor and NextCursor opaque tokens of the previous and the next page (see WithCursor)
+	PrevCursor string
+	NextCursor string
+	HasPrev    bool
+	HasNext    bool
+}
+
+// WithAfter set keyset position, the page starts after item with given sort key values
+// example: bom.WithSort(&bom.Sort{Field: "createdAt", Type: "desc"}).WithAfter(page.Next...)
+func (b *Bom) WithAfter(values ...interface{}) *Bom {
+	b.after, b.before = values, nil
+	return b
+}
+
+// WithBefore set keyset position, the page ends before item with given sort key values
+// example: bom.WithSort(&bom.Sort{Field: "createdAt", Type: "desc"}).WithBefore(page.Prev...)
+func (b *Bom) WithBefore(values ...interface{}) *Bom {
+	b.after, b.before = nil, values
+	return b
+}
+
+// ListWithKeyset keyset pagination by sort keys (_id is used when sort is not set),
+// the next page is detected by fetching one extra item instead of counting,
+// items of the previous page (WithBefore) are passed to callback in the original sort order,
+// nil sort key values (null or missing fields) are ordered first as in mongodb sort
+func (b *Bom) ListWithKeyset(callback func(cursor *mongo.Cursor) error) (*KeysetPage, error) {
+	sortDoc, err := b.getKeysetSort()
+	if err != nil {
+		return nil, err
+	}
+	page := &KeysetPage{Keys: make([]string, 0, len(sortDoc))}
+	for _, e := range sortDoc {
+		page.Keys = append(page.Keys, e.Key)
+	}
+	projection := b.BuildProjection()
+	if projection != nil {
+		if projection, err = keysetProjection(projection, page.Keys); err != nil {
+			return nil, err
+		}
+	}
+
+	after, before := b.after, b.before
+	if b.cursor != "" {
+		values, isBefore, err := b.decodeCursor(sortDoc, b.cursor)
+		if err != nil {
+			return nil, err
+		}
+		after, before = values, nil
+		if isBefore {
+			after, before = nil, values
+		}
+	}
+
+	var filters []Filter
+	if len(before) > 0 {
+		filter, err := keysetFilter(reverseSort(sortDoc), before)
+		if err != nil {
+			return nil, err
+		}
+		filters = append(filters, filter)
+
+		// walk back from position with reversed sort to find where the page starts
+		start, err := b.keysetBoundary(b.keysetCondition(filters...), reverseSort(sortDoc), page.Keys)
+		if err != nil {
+			return nil, err
+		}
+		if start != nil {
+			filter, _ := keysetFilter(sortDoc, start)
+			filters = append(filters, filter)
+		}
+		page.HasPrev = start != nil
+	} else if len(after) > 0 {
+		filter, err := keysetFilter(sortDoc, after)
+		if err != nil {
+			return nil, err
+		}
+		filters = append(filters, filter)
+	}
+
+	findOptions := options.Find()
+	findOptions.SetSort(sortDoc)
+	findOptions.SetLimit(int64(b.limit.Size) + 1)
+	if projection != nil {
+		findOptions.SetProjection(projection)
+	}
+
+	// set query context
+	ctx, cancel := b.getContext()
+	defer cancel()
+
+	cur, err := b.Mongo().Find(ctx, b.keysetCondition(filters...), b.buildFindOptions(findOptions)...)
+	if err != nil {
+		return nil, err
+	}
+	defer cur.Close(ctx)
+
+	var n int32
+	for cur.Next(ctx) {
+		if n == b.limit.Size {
+			page.HasNext = true
+			break
+		}
+		if err := callback(cur); err != nil {
+			return nil, err
+		}
+		page.Next = keysetValues(cur.Current, page.Keys)
+		if n == 0 {
+			page.Prev = page.Next
+		}
+		n++
+	}
+	if err := cur.Err(); err != nil {
+		return nil, err
+	}
+
+	// position of request is the item next to the page in the other direction
+	if n > 0 && len(before) > 0 {
+		page.HasNext = true
+	}
+	if n > 0 && len(after) > 0 {
+		page.HasPrev = true
+	}
+	if !page.HasPrev {
+		page.Prev = nil
+	} else if page.PrevCursor, err = b.encodeCursor(sortDoc, page.Prev, true); err != nil {
+		return nil, err
+	}
+	if !page.HasNext {
+		page.Next = nil
+	} else if page.NextCursor, err = b.encodeCursor(sortDoc, page.Next, false); err != nil {
+		return nil, err
+	}
+	return page, nil
+}
+
+// keysetCondition internal method for condition of bom restricted by keyset filters,
+// raw condition (see WithCondition) is joined with filters by $and
+func (b *Bom) keysetCondition(filters ...Filter) interface{} {
+	if b.condition != nil {
+		if len(filters) == 0 {
+			return b.condition
+		}
+		list := primitive.A{b.condition}
+		for _, filter := range filters {
+			list = append(list, filter.Compile())
+		}
+		return primitive.D{{Key: AndConditionOperator, Value: list}}
+	}
+	// work on a copy, so keyset condition does not leak into the source bom
+	q := b.Clone()
+	for _, filter := range filters {
+		q.WhereFilter(filter)
+	}
+	return q.getCondition()
+}
+
+// keysetBoundary internal method for find sort key values of item which is right before the page,
+// items matching condition are walked with reversed sort, nil is returned when the page starts at the beginning
+func (b *Bom) keysetBoundary(condition interface{}, reversed primitive.D, keys []string) ([]interface{}, error) {
+	projection := make(primitive.M, len(keys))
+	for _, key := range keys {
+		projection[key] = 1
+	}
+	removePathCollisions(projection)
+
+	findOptions := options.Find()
+	findOptions.SetSort(reversed)
+	findOptions.SetSkip(int64(b.limit.Size))
+	findOptions.SetLimit(1)
+	findOptions.SetProjection(projection)
+
+	// set query context
+	ctx, cancel := b.getContext()
+	defer cancel()
+
+	cur, err := b.Mongo().Find(ctx, condition, b.buildFindOptions(findOptions)...)
+	if err != nil {
+		return nil, err
+	}
+	defer cur.Close(ctx)
+
+	var values []interface{}
+	if cur.Next(ctx) {
+		values = keysetValues(cur.Current, keys)
+	}
+	return values, cur.Err()
+}
+
+// reverseSort internal method for reverse directions of sort keys
+func reverseSort(sortDoc primitive.D) primitive.D {
+	reversed := make(primitive.D, 0, len(sortDoc))
+	for _, e := range sortDoc {
+		reversed = append(reversed, primitive.E{Key: e.Key, Value: -e.Value.(int32)})
+	}
+	return reversed
+}
+
+// getKeysetSort internal method for get sort of keyset pagination, only sort by fields can be used
+func (b *Bom) getKeysetSort() (primitive.D, error) {
+	sortDoc := b.getPaginationSort()
+	if len(sortDoc) == 0 {
+		return primitive.D{{Key: "_id", Value: int32(1)}}, nil
+	}
+	for _, e := range sortDoc {
+		if _, ok := e.Value.(int32); !ok {
+			return nil, fmt.Errorf("%w: keyset pagination can not be sorted by %s", ErrInvalidValue, e.Key)
+		}
+	}
+	return sortDoc, nil
+}
+
+// keysetFilter build range condition of items after position:
+// (k1 > v1) OR (k1 = v1 AND k2 > v2) OR ... where > is < for descending keys,
+// nil value (null or missing field) is ordered before all other values as in mongodb sort
+func keysetFilter(sortDoc primitive.D, values []interface{}) (Filter, error) {
+	if len(values) != len(sortDoc) {
+		return nil, fmt.Errorf("%w: keyset position has %d values, sort has %d keys", ErrInvalidValue, len(values), len(sortDoc))
+	}
+	filters := make([]Filter, 0, len(sortDoc))
+	for i, e := range sortDoc {
+		next := keysetNext(e.Key, e.Value.(int32) < 0, values[i])
+		if next == nil {
+			continue
+		}
+		and := make([]Filter, 0, i+1)
+		for j := 0; j < i; j++ {
+			and = append(and, Eq(sortDoc[j].Key, values[j]))
+		}
+		filters = append(filters, And(append(and, next)...))
+	}
+	if len(filters) == 0 {
+		return nil, fmt.Errorf("%w: keyset position can not be null for all keys", ErrInvalidValue)
+	}
+	return Or(filters...), nil
+}
+
+// keysetNext internal method for condition of values which are next to value in sort direction,
+// comparison operators do not match null, so it is handled separately:
+// after null ascending is any not null value, after null descending is nothing,
+// after value descending is less value or null
+func keysetNext(key string, desc bool, value interface{}) Filter {
+	switch {
+	case value == nil && desc:
+		return nil
+	case value == nil:
+		return Condition(key, NotEqualConditionOperator, nil)
+	case desc:
+		return Or(Condition(key, LessConditionOperator, value), Eq(key, nil))
+	}
+	return Condition(key, GreaterConditionOperator, value)
+}
+
+// keysetProjection add sort keys to projection, so position can be read from items:
+// inclusion projection gets all sort keys (_id: 0 is overridden), exclusion projection
+// must not remove any of them, operators ($slice, $meta) keep all other fields like exclusion
+func keysetProjection(projection primitive.M, keys []string) (primitive.M, error) {
+	inclusion := false
+	for key, value := range projection {
+		if key != "_id" && isIncluded(value) {
+			inclusion = true
+			break
+		}
+	}
+	result := make(primitive.M, len(projection)+len(keys))
+	for key, value := range projection {
+		result[key] = value
+	}
+	if inclusion {
+		for _, key := range keys {
+			result[key] = 1
+		}
+		removePathCollisions(result)
+		return result, nil
+	}
+	for key, value := range projection {
+		if !isExcluded(value) {
+			continue
+		}
+		for _, sortKey := range keys {
+			if key == sortKey && key == "_id" {
+				delete(result, key)
+			} else if key == sortKey || strings.HasPrefix(sortKey, key+".") || strings.HasPrefix(key, sortKey+".") {
+				return nil, fmt.Errorf("%w: projection excludes %s of sort key %s", ErrInvalidValue, key, sortKey)
+			}
+		}
+	}
+	return result, nil
+}
+
+// isIncluded internal method for check projection value is plain inclusion (1 or true)
+func isIncluded(value interface{}) bool {
+	switch v := value.(type) {
+	case bool:
+		return v
+	case int:
+		return v != 0
+	case int32:
+		return v != 0
+	case int64:
+		return v != 0
+	case float64:
+		return v != 0
+	}
+	return false
+}
+
+// isExcluded internal method for check projection value removes field
+func isExcluded(value interface{}) bool {
+	switch v := value.(type) {
+	case bool:
+		return !v
+	case int:
+		return v == 0
+	case int32:
+		return v == 0
+	case int64:
+		return v == 0
+	case float64:
+		return v == 0
+	}
+	return false
+}
+
+// keysetValues read sort key values of item, missing keys are nil
+func keysetValues(doc bson.Raw, keys []string) []interface{} {
+	values := make([]interface{}, len(keys))
+	for i, key := range keys {
+		raw, err := doc.LookupErr(strings.Split(key, ".")...)
+		if err != nil {
+			continue
+		}
+		var value interface{}
+		if err := raw.Unmarshal(&value); err == nil {
+			values[i] = value
+		}
+	}
+	return values
+}
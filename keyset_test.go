@@ -0,0 +1,173 @@
+package bom
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestKeysetFilter(t *testing.T) {
+	b := &Bom{}
+	b.WithSort(&Sort{Field: "createdAt", Type: "desc"}).WithSort(&Sort{Field: "name", Type: "asc"})
+	sortDoc, err := b.getKeysetSort()
+	if err != nil {
+		t.Fatalf("getKeysetSort() error = %v", err)
+	}
+
+	filter, err := keysetFilter(sortDoc, []interface{}{10, "jo", 7})
+	if err != nil {
+		t.Fatalf("keysetFilter() error = %v", err)
+	}
+	want := primitive.D{{Key: OrConditionOperator, Value: primitive.A{
+		primitive.D{{Key: OrConditionOperator, Value: primitive.A{
+			primitive.D{{Key: "createdAt", Value: primitive.D{{Key: LessConditionOperator, Value: 10}}}},
+			primitive.D{{Key: "createdAt", Value: nil}},
+		}}},
+		primitive.D{{Key: AndConditionOperator, Value: primitive.A{
+			primitive.D{{Key: "createdAt", Value: 10}},
+			primitive.D{{Key: "name", Value: primitive.D{{Key: GreaterConditionOperator, Value: "jo"}}}},
+		}}},
+		primitive.D{{Key: AndConditionOperator, Value: primitive.A{
+			primitive.D{{Key: "createdAt", Value: 10}},
+			primitive.D{{Key: "name", Value: "jo"}},
+			primitive.D{{Key: "_id", Value: primitive.D{{Key: GreaterConditionOperator, Value: 7}}}},
+		}}},
+	}}}
+	if got := filter.Compile(); !reflect.DeepEqual(got, want) {
+		t.Errorf("keysetFilter() = %v, want %v", got, want)
+	}
+
+	if _, err := keysetFilter(sortDoc, []interface{}{10}); !errors.Is(err, ErrInvalidValue) {
+		t.Errorf("keysetFilter() error = %v, want %v", err, ErrInvalidValue)
+	}
+
+	// null is ordered first: nothing is after it descending, any value is after it ascending
+	filter, err = keysetFilter(sortDoc, []interface{}{nil, nil, 7})
+	if err != nil {
+		t.Fatalf("keysetFilter() error = %v", err)
+	}
+	want = primitive.D{{Key: OrConditionOperator, Value: primitive.A{
+		primitive.D{{Key: AndConditionOperator, Value: primitive.A{
+			primitive.D{{Key: "createdAt", Value: nil}},
+			primitive.D{{Key: "name", Value: primitive.D{{Key: NotEqualConditionOperator, Value: nil}}}},
+		}}},
+		primitive.D{{Key: AndConditionOperator, Value: primitive.A{
+			primitive.D{{Key: "createdAt", Value: nil}},
+			primitive.D{{Key: "name", Value: nil}},
+			primitive.D{{Key: "_id", Value: primitive.D{{Key: GreaterConditionOperator, Value: 7}}}},
+		}}},
+	}}}
+	if got := filter.Compile(); !reflect.DeepEqual(got, want) {
+		t.Errorf("keysetFilter() = %v, want %v", got, want)
+	}
+
+	if _, err := keysetFilter(primitive.D{{Key: "_id", Value: int32(-1)}}, []interface{}{nil}); !errors.Is(err, ErrInvalidValue) {
+		t.Errorf("keysetFilter() error = %v, want %v", err, ErrInvalidValue)
+	}
+}
+
+func TestBom_getKeysetSort(t *testing.T) {
+	got, err := (&Bom{}).getKeysetSort()
+	if want := (primitive.D{{Key: "_id", Value: int32(1)}}); err != nil || !reflect.DeepEqual(got, want) {
+		t.Errorf("getKeysetSort() = %v, %v, want %v", got, err, want)
+	}
+
+	b := &Bom{}
+	b.WithSort(&Sort{Field: "score", Type: SortTypeTextScore})
+	if _, err := b.getKeysetSort(); !errors.Is(err, ErrInvalidValue) {
+		t.Errorf("getKeysetSort() error = %v, want %v", err, ErrInvalidValue)
+	}
+}
+
+func TestKeysetValues(t *testing.T) {
+	doc, _ := bson.Marshal(primitive.D{{Key: "name", Value: "jo"}, {Key: "meta", Value: primitive.D{{Key: "rank", Value: int32(3)}}}})
+	got := keysetValues(doc, []string{"meta.rank", "name", "missing"})
+	if want := []interface{}{int32(3), "jo", nil}; !reflect.DeepEqual(got, want) {
+		t.Errorf("keysetValues() = %v, want %v", got, want)
+	}
+}
+
+func TestKeysetProjection(t *testing.T) {
+	keys := []string{"meta.rank", "_id"}
+	tests := []struct {
+		name       string
+		projection primitive.M
+		want       primitive.M
+		wantErr    bool
+	}{
+		{name: "inclusion gets sort keys", projection: primitive.M{"name": 1},
+			want: primitive.M{"name": 1, "meta.rank": 1, "_id": 1}},
+		{name: "inclusion overrides _id exclusion", projection: primitive.M{"name": 1, "_id": 0},
+			want: primitive.M{"name": 1, "meta.rank": 1, "_id": 1}},
+		{name: "inclusion of parent path", projection: primitive.M{"meta": true},
+			want: primitive.M{"meta": true, "_id": 1}},
+		{name: "exclusion keeps other fields", projection: primitive.M{"history": 0},
+			want: primitive.M{"history": 0}},
+		{name: "exclusion overrides _id exclusion", projection: primitive.M{"history": int32(0), "_id": false},
+			want: primitive.M{"history": int32(0)}},
+		{name: "only _id exclusion", projection: primitive.M{"_id": 0},
+			want: primitive.M{}},
+		{name: "slice keeps other fields", projection: primitive.M{"comments": primitive.M{"$slice": primitive.A{0, 5}}},
+			want: primitive.M{"comments": primitive.M{"$slice": primitive.A{0, 5}}}},
+		{name: "text score keeps other fields", projection: primitive.M{"score": primitive.M{"$meta": "textScore"}, "_id": 0},
+			want: primitive.M{"score": primitive.M{"$meta": "textScore"}}},
+		{name: "slice with inclusion", projection: primitive.M{"name": 1, "comments": primitive.M{"$slice": 5}},
+			want: primitive.M{"name": 1, "comments": primitive.M{"$slice": 5}, "meta.rank": 1, "_id": 1}},
+		{name: "exclusion of sort key", projection: primitive.M{"meta.rank": 0}, wantErr: true},
+		{name: "exclusion of sort key parent", projection: primitive.M{"meta": false}, wantErr: true},
+		{name: "exclusion of sort key child", projection: primitive.M{"meta.rank.value": 0}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := keysetProjection(tt.projection, keys)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidValue) {
+					t.Errorf("keysetProjection() error = %v, want %v", err, ErrInvalidValue)
+				}
+				return
+			}
+			if err != nil || !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("keysetProjection() = %v, %v, want %v", got, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestReverseSort(t *testing.T) {
+	got := reverseSort(primitive.D{{Key: "createdAt", Value: int32(-1)}, {Key: "_id", Value: int32(1)}})
+	if want := (primitive.D{{Key: "createdAt", Value: int32(1)}, {Key: "_id", Value: int32(-1)}}); !reflect.DeepEqual(got, want) {
+		t.Errorf("reverseSort() = %v, want %v", got, want)
+	}
+}
+
+func TestBom_keysetCondition(t *testing.T) {
+	filter := Gt("_id", 7)
+
+	b := (&Bom{}).WithCondition(primitive.M{"status": "a"})
+	want := primitive.D{{Key: AndConditionOperator, Value: primitive.A{
+		primitive.M{"status": "a"},
+		primitive.D{{Key: "_id", Value: primitive.D{{Key: GreaterConditionOperator, Value: 7}}}},
+	}}}
+	if got := b.keysetCondition(filter); !reflect.DeepEqual(got, want) {
+		t.Errorf("keysetCondition() with raw condition = %v, want %v", got, want)
+	}
+	if got := b.keysetCondition(); !reflect.DeepEqual(got, primitive.M{"status": "a"}) {
+		t.Errorf("keysetCondition() without filters = %v, want raw condition", got)
+	}
+
+	b = (&Bom{}).WhereEq("status", "a")
+	want = primitive.D{{Key: AndConditionOperator, Value: primitive.A{
+		primitive.D{{Key: "status", Value: "a"}},
+		primitive.D{{Key: "_id", Value: primitive.D{{Key: GreaterConditionOperator, Value: 7}}}},
+	}}}
+	if got := b.keysetCondition(filter); !reflect.DeepEqual(got, want) {
+		t.Errorf("keysetCondition() = %v, want %v", got, want)
+	}
+	// keyset filter does not leak into the source bom
+	if got := b.getCondition(); !reflect.DeepEqual(got, primitive.D{{Key: "status", Value: "a"}}) {
+		t.Errorf("getCondition() = %v, want source condition", got)
+	}
+}
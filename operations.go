@@ -0,0 +1,142 @@
+package bom
+
+import "go.mongodb.org/mongo-driver/mongo/options"
+
+// buildFindOptions internal method for build options of find operation,
+// custom options are applied first, then query settings and operation options
+func (b *Bom) buildFindOptions(opts ...*options.FindOptions) []*options.FindOptions {
+	common := options.Find()
+	if b.collation != nil {
+		common.SetCollation(b.collation)
+	}
+	if b.hint != nil {
+		common.SetHint(b.hint)
+	}
+	if b.maxTime > 0 {
+		common.SetMaxTime(b.maxTime)
+	}
+	if b.comment != "" {
+		common.SetComment(b.comment)
+	}
+	return append(append(b.options.clone().findOptions, common), opts...)
+}
+
+// buildFindOneOptions internal method for build options of find one operation
+func (b *Bom) buildFindOneOptions(opts ...*options.FindOneOptions) []*options.FindOneOptions {
+	common := options.FindOne()
+	if b.collation != nil {
+		common.SetCollation(b.collation)
+	}
+	if b.hint != nil {
+		common.SetHint(b.hint)
+	}
+	if b.maxTime > 0 {
+		common.SetMaxTime(b.maxTime)
+	}
+	if b.comment != "" {
+		common.SetComment(b.comment)
+	}
+	return append(append(b.options.clone().findOneOptions, common), opts...)
+}
+
+// buildFindOneAndUpdateOptions internal method for build options of find one and update operation
+func (b *Bom) buildFindOneAndUpdateOptions(opts ...*options.FindOneAndUpdateOptions) []*options.FindOneAndUpdateOptions {
+	common := options.FindOneAndUpdate()
+	if b.collation != nil {
+		common.SetCollation(b.collation)
+	}
+	if b.maxTime > 0 {
+		common.SetMaxTime(b.maxTime)
+	}
+	return append(append(b.options.clone().findOneAndUpdateOptions, common), opts...)
+}
+
+// buildFindOneAndDeleteOptions internal method for build options of find one and delete operation
+func (b *Bom) buildFindOneAndDeleteOptions(opts ...*options.FindOneAndDeleteOptions) []*options.FindOneAndDeleteOptions {
+	common := options.FindOneAndDelete()
+	if b.collation != nil {
+		common.SetCollation(b.collation)
+	}
+	if b.maxTime > 0 {
+		common.SetMaxTime(b.maxTime)
+	}
+	return append([]*options.FindOneAndDeleteOptions{common}, opts...)
+}
+
+// buildCountOptions internal method for build options of count operation
+func (b *Bom) buildCountOptions(opts ...*options.CountOptions) []*options.CountOptions {
+	common := options.Count()
+	if b.collation != nil {
+		common.SetCollation(b.collation)
+	}
+	if b.hint != nil {
+		common.SetHint(b.hint)
+	}
+	if b.maxTime > 0 {
+		common.SetMaxTime(b.maxTime)
+	}
+	return append([]*options.CountOptions{common}, opts...)
+}
+
+// buildEstimatedCountOptions internal method for build options of estimated count operation
+func (b *Bom) buildEstimatedCountOptions(opts ...*options.EstimatedDocumentCountOptions) []*options.EstimatedDocumentCountOptions {
+	common := options.EstimatedDocumentCount()
+	if b.maxTime > 0 {
+		common.SetMaxTime(b.maxTime)
+	}
+	return append([]*options.EstimatedDocumentCountOptions{common}, opts...)
+}
+
+// buildUpdateOptions internal method for build options of update operation
+func (b *Bom) buildUpdateOptions(opts ...*options.UpdateOptions) []*options.UpdateOptions {
+	common := options.Update()
+	if b.collation != nil {
+		common.SetCollation(b.collation)
+	}
+	return append(append(b.options.clone().updateOptions, common), opts...)
+}
+
+// buildDeleteOptions internal method for build options of delete operation
+func (b *Bom) buildDeleteOptions(opts ...*options.DeleteOptions) []*options.DeleteOptions {
+	common := options.Delete()
+	if b.collation != nil {
+		common.SetCollation(b.collation)
+	}
+	return append([]*options.DeleteOptions{common}, opts...)
+}
+
+// buildAggregateOptions internal method for build options of aggregate operation
+func (b *Bom) buildAggregateOptions(opts ...*options.AggregateOptions) []*options.AggregateOptions {
+	common := options.Aggregate()
+	if b.collation != nil {
+		common.SetCollation(b.collation)
+	}
+	if b.hint != nil {
+		common.SetHint(b.hint)
+	}
+	if b.maxTime > 0 {
+		common.SetMaxTime(b.maxTime)
+	}
+	if b.comment != "" {
+		common.SetComment(b.comment)
+	}
+	return append(append(b.options.clone().aggregateOptions, common), opts...)
+}
+
+// buildInsertOneOptions internal method for build options of insert one operation
+func (b *Bom) buildInsertOneOptions(opts ...*options.InsertOneOptions) []*options.InsertOneOptions {
+	return append(b.options.clone().insertOptions, opts...)
+}
+
+// buildInsertManyOptions internal method for build options of insert many operation,
+// document validation bypass of custom insert options is applied as well
+func (b *Bom) buildInsertManyOptions(opts ...*options.InsertManyOptions) []*options.InsertManyOptions {
+	var result []*options.InsertManyOptions
+	for _, o := range b.options.insertOptions {
+		if o != nil && o.BypassDocumentValidation != nil {
+			result = append(result, options.InsertMany().SetBypassDocumentValidation(*o.BypassDocumentValidation))
+		}
+	}
+	result = append(result, b.options.clone().insertManyOptions...)
+	return append(result, opts...)
+}
@@ -0,0 +1,90 @@
+package bom
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/readconcern"
+)
+
+func TestBom_buildOptions_Collation(t *testing.T) {
+	collation := &options.Collation{Locale: "en", Strength: 2}
+	b := (&Bom{}).WithCollation(collation)
+
+	tests := []struct {
+		name string
+		got  *options.Collation
+	}{
+		{name: "find", got: options.MergeFindOptions(b.buildFindOptions()...).Collation},
+		{name: "find one", got: options.MergeFindOneOptions(b.buildFindOneOptions()...).Collation},
+		{name: "find one and update", got: options.MergeFindOneAndUpdateOptions(b.buildFindOneAndUpdateOptions()...).Collation},
+		{name: "find one and delete", got: options.MergeFindOneAndDeleteOptions(b.buildFindOneAndDeleteOptions()...).Collation},
+		{name: "count", got: options.MergeCountOptions(b.buildCountOptions()...).Collation},
+		{name: "update", got: options.MergeUpdateOptions(b.buildUpdateOptions()...).Collation},
+		{name: "delete", got: options.MergeDeleteOptions(b.buildDeleteOptions()...).Collation},
+		{name: "aggregate", got: options.MergeAggregateOptions(b.buildAggregateOptions()...).Collation},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if !reflect.DeepEqual(tt.got, collation) {
+				t.Errorf("collation = %v, want %v", tt.got, collation)
+			}
+		})
+	}
+
+	// operation options override query settings
+	other := &options.Collation{Locale: "fr"}
+	if got := options.MergeFindOptions(b.buildFindOptions(options.Find().SetCollation(other))...).Collation; got != other {
+		t.Errorf("collation = %v, want %v", got, other)
+	}
+}
+
+func TestBom_buildInsertManyOptions(t *testing.T) {
+	b := &Bom{}
+	b.SetInsertOptions(options.InsertOne().SetBypassDocumentValidation(true))
+	b.SetInsertManyOptions(options.InsertMany().SetOrdered(false))
+
+	got := options.MergeInsertManyOptions(b.buildInsertManyOptions()...)
+	if got.BypassDocumentValidation == nil || !*got.BypassDocumentValidation {
+		t.Errorf("BypassDocumentValidation = %v, want true", got.BypassDocumentValidation)
+	}
+	if got.Ordered == nil || *got.Ordered {
+		t.Errorf("Ordered = %v, want false", got.Ordered)
+	}
+}
+
+func TestBom_buildOptions_Settings(t *testing.T) {
+	b := (&Bom{}).WithHint("name_1").WithMaxTime(time.Second).WithComment("list users")
+
+	find := options.MergeFindOptions(b.buildFindOptions()...)
+	if find.Hint != "name_1" || *find.MaxTime != time.Second || *find.Comment != "list users" {
+		t.Errorf("find options = %v, %v, %v", find.Hint, *find.MaxTime, *find.Comment)
+	}
+	count := options.MergeCountOptions(b.buildCountOptions()...)
+	if count.Hint != "name_1" || *count.MaxTime != time.Second {
+		t.Errorf("count options = %v, %v", count.Hint, *count.MaxTime)
+	}
+	aggregate := options.MergeAggregateOptions(b.buildAggregateOptions()...)
+	if aggregate.Hint != "name_1" || *aggregate.MaxTime != time.Second || *aggregate.Comment != "list users" {
+		t.Errorf("aggregate options = %v, %v, %v", aggregate.Hint, *aggregate.MaxTime, *aggregate.Comment)
+	}
+	if estimated := options.MergeEstimatedDocumentCountOptions(b.buildEstimatedCountOptions()...); *estimated.MaxTime != time.Second {
+		t.Errorf("estimated count max time = %v", *estimated.MaxTime)
+	}
+}
+
+func TestBom_Session_Settings(t *testing.T) {
+	rc := readconcern.Majority()
+	base := (&Bom{limit: &Limit{Page: 1, Size: DefaultSize}}).
+		WithHint("name_1").WithMaxTime(time.Second).WithComment("list users").WithReadConcern(rc)
+
+	session := base.Session()
+	if session.hint != nil {
+		t.Errorf("Session() hint = %v, want nil", session.hint)
+	}
+	if session.maxTime != time.Second || session.comment != "list users" || session.readConcern != rc {
+		t.Errorf("Session() lost settings: %v, %v, %v", session.maxTime, session.comment, session.readConcern)
+	}
+}
@@ -2,8 +2,13 @@ package bom
 
 import (
 	"context"
-	"go.mongodb.org/mongo-driver/mongo"
 	"time"
+
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/readconcern"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
+	"go.mongodb.org/mongo-driver/mongo/writeconcern"
 )
 
 // Option bom option type
@@ -57,9 +62,90 @@ func SetModel(document interface{}) Option {
 	}
 }
 
-// Deprecated: SetContext method should not be used
+// SetCollation set default collation of queries
+func SetCollation(collation *options.Collation) Option {
+	return func(b *Bom) error {
+		b.collation = collation
+		return nil
+	}
+}
+
+// SetLowercaseSort lowercase sort field names (compatibility with previous versions)
+func SetLowercaseSort(lowercase bool) Option {
+	return func(b *Bom) error {
+		b.lowercaseSort = lowercase
+		return nil
+	}
+}
+
+// SetTieBreaker enable or disable _id as the last sort key of paginated queries
+func SetTieBreaker(enabled bool) Option {
+	return func(b *Bom) error {
+		b.disableTieBreaker = !enabled
+		return nil
+	}
+}
+
+// SetContext set parent context for queries
 func SetContext(ctx context.Context) Option {
 	return func(b *Bom) error {
+		b.ctx = ctx
+		return nil
+	}
+}
+
+// SetMaxTime set default server side time limit of queries
+func SetMaxTime(d time.Duration) Option {
+	return func(b *Bom) error {
+		b.maxTime = d
+		return nil
+	}
+}
+
+// SetReadPreference set default read preference of queries
+func SetReadPreference(pref *readpref.ReadPref) Option {
+	return func(b *Bom) error {
+		b.readPreference = pref
+		return nil
+	}
+}
+
+// SetReadConcern set default read concern of queries
+func SetReadConcern(rc *readconcern.ReadConcern) Option {
+	return func(b *Bom) error {
+		b.readConcern = rc
+		return nil
+	}
+}
+
+// SetWriteConcern set default write concern of insert, update and delete operations
+func SetWriteConcern(wc *writeconcern.WriteConcern) Option {
+	return func(b *Bom) error {
+		b.writeConcern = wc
+		return nil
+	}
+}
+
+// SetCursorKey set key for HMAC signature of cursor tokens
+func SetCursorKey(key []byte) Option {
+	return func(b *Bom) error {
+		b.cursorKey = key
+		return nil
+	}
+}
+
+// SetCountStrategy set default count strategy of ListWithPagination
+func SetCountStrategy(strategy CountStrategy) Option {
+	return func(b *Bom) error {
+		b.countStrategy = strategy
+		return nil
+	}
+}
+
+// SetConcurrentCount run count and find of ListWithPagination in parallel
+func SetConcurrentCount(enabled bool) Option {
+	return func(b *Bom) error {
+		b.concurrentCount = enabled
 		return nil
 	}
 }
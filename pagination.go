@@ -1,13 +1,48 @@
 package bom
 
-import "math"
+import (
+	"math"
+	"net/url"
+	"strconv"
+	"strings"
+)
 
 // Pagination data
 type Pagination struct {
-	TotalCount  int32
-	TotalPages  int32
+	TotalCount  int64
+	TotalPages  int64
 	CurrentPage int32
 	Size        int32
+
+	// Count way of counting TotalCount, it is a lower bound for skipped count and capped count with Capped
+	Count  CountMode
+	Capped bool
+
+	// more there are items after page, used instead of total for skipped and capped count
+	more bool
+}
+
+// PaginationMeta JSON:API style meta of page
+type PaginationMeta struct {
+	TotalCount  int64     `json:"totalCount"`
+	TotalPages  int64     `json:"totalPages"`
+	CurrentPage int32     `json:"currentPage"`
+	Size        int32     `json:"size"`
+	FirstItem   int64     `json:"firstItem"`
+	LastItem    int64     `json:"lastItem"`
+	HasPrev     bool      `json:"hasPrev"`
+	HasNext     bool      `json:"hasNext"`
+	Count       CountMode `json:"count"`
+	Capped      bool      `json:"capped,omitempty"`
+}
+
+// PaginationLinks JSON:API style links of page
+type PaginationLinks struct {
+	Self  string `json:"self"`
+	First string `json:"first"`
+	Prev  string `json:"prev,omitempty"`
+	Next  string `json:"next,omitempty"`
+	Last  string `json:"last,omitempty"`
 }
 
 // NewPagination create pagination
@@ -22,7 +57,7 @@ func NewPagination(page int32, size int32) *Pagination {
 }
 
 // WithTotal enrich pagination total counts
-func (p *Pagination) WithTotal(count int32) *Pagination {
+func (p *Pagination) WithTotal(count int64) *Pagination {
 	p.TotalCount = count
 	p.TotalPages = p.getTotalPages()
 	return p
@@ -41,11 +76,176 @@ func (p *Pagination) CalculateOffset() (int32, int32) {
 	return p.Size, offset
 }
 
+// HasNext there is a page after current one
+func (p *Pagination) HasNext() bool {
+	if p.Count == CountSkipped || p.Count == CountCapped {
+		return p.more
+	}
+	return int64(p.CurrentPage) < p.TotalPages
+}
+
+// HasPrev there is a page before current one
+func (p *Pagination) HasPrev() bool {
+	return p.CurrentPage > 1
+}
+
+// NextPage number of the next page, 0 when current page is the last one
+func (p *Pagination) NextPage() int32 {
+	if !p.HasNext() {
+		return 0
+	}
+	return p.CurrentPage + 1
+}
+
+// PrevPage number of the previous page, 0 when current page is the first one
+func (p *Pagination) PrevPage() int32 {
+	if !p.HasPrev() {
+		return 0
+	}
+	return p.CurrentPage - 1
+}
+
+// FirstItem 1-based position of the first item of page, 0 when page is empty
+func (p *Pagination) FirstItem() int64 {
+	offset := p.offset()
+	if offset >= p.TotalCount {
+		return 0
+	}
+	return offset + 1
+}
+
+// LastItem 1-based position of the last item of page, 0 when page is empty
+func (p *Pagination) LastItem() int64 {
+	if p.FirstItem() == 0 {
+		return 0
+	}
+	last := p.offset() + int64(p.Size)
+	if last > p.TotalCount {
+		last = p.TotalCount
+	}
+	return last
+}
+
+// Meta JSON:API style meta of page
+func (p *Pagination) Meta() PaginationMeta {
+	return PaginationMeta{
+		TotalCount:  p.TotalCount,
+		TotalPages:  p.TotalPages,
+		CurrentPage: p.CurrentPage,
+		Size:        p.Size,
+		FirstItem:   p.FirstItem(),
+		LastItem:    p.LastItem(),
+		HasPrev:     p.HasPrev(),
+		HasNext:     p.HasNext(),
+		Count:       p.Count,
+		Capped:      p.Capped,
+	}
+}
+
+// Links JSON:API style links of page, page and size parameters are set to base url,
+// last link is empty when total is not known (skipped or capped count),
+// see QueryParams.Links for custom parameter names
+// example: p.Links("https://api.example.com/users?status=active")
+func (p *Pagination) Links(baseURL string) (PaginationLinks, error) {
+	return p.links(baseURL, DefaultPageParam, DefaultSizeParam)
+}
+
+// LinkHeader RFC 8288 Link header of page example: <https://api.example.com/users?page=3&size=20>; rel="next", ...
+func (p *Pagination) LinkHeader(baseURL string) (string, error) {
+	return p.linkHeader(baseURL, DefaultPageParam, DefaultSizeParam)
+}
+
+// links internal method for build links of page with names of page and size parameters
+func (p *Pagination) links(baseURL, pageParam, sizeParam string) (PaginationLinks, error) {
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return PaginationLinks{}, err
+	}
+	pageURL := func(page int64) string {
+		return p.pageURL(u, page, pageParam, sizeParam)
+	}
+	links := PaginationLinks{
+		Self:  pageURL(int64(p.CurrentPage)),
+		First: pageURL(1),
+	}
+	if p.Count != CountSkipped && !p.Capped {
+		links.Last = pageURL(p.lastPage())
+	}
+	if p.HasPrev() {
+		links.Prev = pageURL(int64(p.PrevPage()))
+	}
+	if p.HasNext() {
+		links.Next = pageURL(int64(p.NextPage()))
+	}
+	return links, nil
+}
+
+// linkHeader internal method for build Link header of page with names of page and size parameters
+func (p *Pagination) linkHeader(baseURL, pageParam, sizeParam string) (string, error) {
+	links, err := p.links(baseURL, pageParam, sizeParam)
+	if err != nil {
+		return "", err
+	}
+	var list []string
+	for _, link := range []struct{ rel, url string }{
+		{"first", links.First},
+		{"prev", links.Prev},
+		{"next", links.Next},
+		{"last", links.Last},
+	} {
+		if link.url != "" {
+			list = append(list, "<"+link.url+`>; rel="`+link.rel+`"`)
+		}
+	}
+	return strings.Join(list, ", "), nil
+}
+
+// withPage internal method for enrich pagination by items of page when total is not exact,
+// total is raised to the number of items seen so far
+func (p *Pagination) withPage(items int64, more bool) *Pagination {
+	p.more = more
+	if seen := p.offset() + items; seen > p.TotalCount {
+		p.WithTotal(seen)
+	}
+	return p
+}
+
+// offset internal method for get number of items before page
+func (p *Pagination) offset() int64 {
+	page := int64(p.CurrentPage)
+	if page < 1 {
+		page = 1
+	}
+	return (page - 1) * int64(p.Size)
+}
+
+// lastPage internal method for get number of the last page, an empty result has one page
+func (p *Pagination) lastPage() int64 {
+	if p.TotalPages < 1 {
+		return 1
+	}
+	return p.TotalPages
+}
+
+// pageURL internal method for build url of page
+func (p *Pagination) pageURL(base *url.URL, page int64, pageParam, sizeParam string) string {
+	u := *base
+	query := u.Query()
+	query.Set(pageParam, strconv.FormatInt(page, 10))
+	query.Set(sizeParam, strconv.FormatInt(int64(p.Size), 10))
+	u.RawQuery = query.Encode()
+	return u.String()
+}
+
 // getTotalPages internal method get total pages
-func (p *Pagination) getTotalPages() int32 {
-	d := float64(p.TotalCount) / float64(p.Size)
-	if d < 0 {
-		d = 1
+func (p *Pagination) getTotalPages() int64 {
+	if p.Size <= 0 {
+		return 0
+	}
+	if p.TotalCount < 0 {
+		return 1
 	}
-	return int32(math.Ceil(d))
+	// integer division keeps precision of big counts
+	size := int64(p.Size)
+	return (p.TotalCount + size - 1) / size
 }
@@ -1,6 +1,7 @@
 package bom
 
 import (
+	"encoding/json"
 	"reflect"
 	"testing"
 )
@@ -94,13 +95,13 @@ func TestPagination_CalculateOffset(t *testing.T) {
 
 func TestPagination_WithTotal(t *testing.T) {
 	type fields struct {
-		TotalCount  int32
-		TotalPages  int32
+		TotalCount  int64
+		TotalPages  int64
 		CurrentPage int32
 		Size        int32
 	}
 	type args struct {
-		count int32
+		count int64
 	}
 	tests := []struct {
 		name   string
@@ -109,11 +110,11 @@ func TestPagination_WithTotal(t *testing.T) {
 		want   *Pagination
 	}{
 		{name: "init", fields: struct {
-			TotalCount  int32
-			TotalPages  int32
+			TotalCount  int64
+			TotalPages  int64
 			CurrentPage int32
 			Size        int32
-		}{CurrentPage: 1, Size: 50}, args: struct{ count int32 }{count: 100}, want: &Pagination{
+		}{CurrentPage: 1, Size: 50}, args: struct{ count int64 }{count: 100}, want: &Pagination{
 			TotalCount:  100,
 			TotalPages:  2,
 			CurrentPage: 1,
@@ -137,36 +138,43 @@ func TestPagination_WithTotal(t *testing.T) {
 
 func TestPagination_getTotalPages(t *testing.T) {
 	type fields struct {
-		TotalCount  int32
-		TotalPages  int32
+		TotalCount  int64
+		TotalPages  int64
 		CurrentPage int32
 		Size        int32
 	}
 	tests := []struct {
 		name   string
 		fields fields
-		want   int32
+		want   int64
 	}{
 		{name: "case 1", fields: struct {
-			TotalCount  int32
-			TotalPages  int32
+			TotalCount  int64
+			TotalPages  int64
 			CurrentPage int32
 			Size        int32
 		}{TotalCount: 100, Size: 50}, want: 2},
 
 		{name: "case 2", fields: struct {
-			TotalCount  int32
-			TotalPages  int32
+			TotalCount  int64
+			TotalPages  int64
 			CurrentPage int32
 			Size        int32
 		}{TotalCount: 80, Size: 50}, want: 2},
 
 		{name: "case 3", fields: struct {
-			TotalCount  int32
-			TotalPages  int32
+			TotalCount  int64
+			TotalPages  int64
 			CurrentPage int32
 			Size        int32
 		}{TotalCount: 51, Size: 50}, want: 2},
+
+		{name: "case 4", fields: struct {
+			TotalCount  int64
+			TotalPages  int64
+			CurrentPage int32
+			Size        int32
+		}{TotalCount: 1<<53 + 1, Size: 1}, want: 1<<53 + 1},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -182,3 +190,115 @@ func TestPagination_getTotalPages(t *testing.T) {
 		})
 	}
 }
+
+func TestPagination_Navigation(t *testing.T) {
+	tests := []struct {
+		name                string
+		p                   *Pagination
+		hasPrev, hasNext    bool
+		prevPage, nextPage  int32
+		firstItem, lastItem int64
+	}{
+		{name: "first page", p: NewPagination(1, 20).WithTotal(45),
+			hasNext: true, nextPage: 2, firstItem: 1, lastItem: 20},
+		{name: "middle page", p: NewPagination(2, 20).WithTotal(45),
+			hasPrev: true, hasNext: true, prevPage: 1, nextPage: 3, firstItem: 21, lastItem: 40},
+		{name: "last page", p: NewPagination(3, 20).WithTotal(45),
+			hasPrev: true, prevPage: 2, firstItem: 41, lastItem: 45},
+		{name: "out of range", p: NewPagination(5, 20).WithTotal(45),
+			hasPrev: true, prevPage: 4},
+		{name: "empty", p: NewPagination(1, 20).WithTotal(0)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.p.HasPrev(); got != tt.hasPrev {
+				t.Errorf("HasPrev() = %v, want %v", got, tt.hasPrev)
+			}
+			if got := tt.p.HasNext(); got != tt.hasNext {
+				t.Errorf("HasNext() = %v, want %v", got, tt.hasNext)
+			}
+			if got := tt.p.PrevPage(); got != tt.prevPage {
+				t.Errorf("PrevPage() = %v, want %v", got, tt.prevPage)
+			}
+			if got := tt.p.NextPage(); got != tt.nextPage {
+				t.Errorf("NextPage() = %v, want %v", got, tt.nextPage)
+			}
+			if got := tt.p.FirstItem(); got != tt.firstItem {
+				t.Errorf("FirstItem() = %v, want %v", got, tt.firstItem)
+			}
+			if got := tt.p.LastItem(); got != tt.lastItem {
+				t.Errorf("LastItem() = %v, want %v", got, tt.lastItem)
+			}
+		})
+	}
+}
+
+func TestPagination_LinkHeader(t *testing.T) {
+	p := NewPagination(2, 20).WithTotal(45)
+	got, err := p.LinkHeader("https://api.example.com/users?status=active")
+	if err != nil {
+		t.Fatalf("LinkHeader() error = %v", err)
+	}
+	want := `<https://api.example.com/users?page=1&size=20&status=active>; rel="first", ` +
+		`<https://api.example.com/users?page=1&size=20&status=active>; rel="prev", ` +
+		`<https://api.example.com/users?page=3&size=20&status=active>; rel="next", ` +
+		`<https://api.example.com/users?page=3&size=20&status=active>; rel="last"`
+	if got != want {
+		t.Errorf("LinkHeader() = %v, want %v", got, want)
+	}
+
+	links, _ := NewPagination(1, 20).WithTotal(0).Links("/users")
+	if wantLinks := (PaginationLinks{Self: "/users?page=1&size=20", First: "/users?page=1&size=20", Last: "/users?page=1&size=20"}); links != wantLinks {
+		t.Errorf("Links() = %v, want %v", links, wantLinks)
+	}
+}
+
+func TestPagination_withPage(t *testing.T) {
+	tests := []struct {
+		name      string
+		p         *Pagination
+		items     int64
+		more      bool
+		wantTotal int64
+		wantPages int64
+		wantNext  bool
+		wantLast  bool
+	}{
+		{name: "skipped count with next page", p: &Pagination{CurrentPage: 3, Size: 20, Count: CountSkipped},
+			items: 20, more: true, wantTotal: 60, wantPages: 3, wantNext: true},
+		{name: "skipped count last page", p: &Pagination{CurrentPage: 3, Size: 20, Count: CountSkipped},
+			items: 5, wantTotal: 45, wantPages: 3},
+		{name: "capped count beyond cap", p: (&Pagination{CurrentPage: 6, Size: 20, Count: CountCapped, Capped: true}).WithTotal(100),
+			items: 20, more: true, wantTotal: 120, wantPages: 6, wantNext: true},
+		{name: "capped count below cap", p: (&Pagination{CurrentPage: 1, Size: 20, Count: CountCapped}).WithTotal(30),
+			items: 20, more: true, wantTotal: 30, wantPages: 2, wantNext: true, wantLast: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := tt.p.withPage(tt.items, tt.more)
+			if p.TotalCount != tt.wantTotal || p.TotalPages != tt.wantPages {
+				t.Errorf("withPage() total = %v, pages = %v, want %v, %v", p.TotalCount, p.TotalPages, tt.wantTotal, tt.wantPages)
+			}
+			if got := p.HasNext(); got != tt.wantNext {
+				t.Errorf("HasNext() = %v, want %v", got, tt.wantNext)
+			}
+			links, _ := p.Links("/users")
+			if got := links.Last != ""; got != tt.wantLast {
+				t.Errorf("Links() last = %q, want link %v", links.Last, tt.wantLast)
+			}
+		})
+	}
+}
+
+func TestPagination_Meta(t *testing.T) {
+	p := (&Pagination{CurrentPage: 1, Size: 20, Count: CountCapped, Capped: true}).WithTotal(10000)
+	data, err := json.Marshal(p.Meta())
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	want := `{"totalCount":10000,"totalPages":500,"currentPage":1,"size":20,"firstItem":1,"lastItem":20,` +
+		`"hasPrev":false,"hasNext":false,"count":"capped","capped":true}`
+	if string(data) != want {
+		t.Errorf("Meta() = %s, want %s", data, want)
+	}
+}
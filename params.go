@@ -0,0 +1,261 @@
+package bom
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// FieldType type of http query parameter value
+type FieldType int
+
+// Define field types
+const (
+	FieldString FieldType = iota
+	FieldInt
+	FieldFloat
+	FieldBool
+	FieldTime
+	FieldObjectID
+)
+
+// Define common query parameters names
+const (
+	DefaultPageParam = "page"
+	DefaultSizeParam = "size"
+	DefaultSortParam = "sort"
+)
+
+// ParamOperators operators of http query parameters example: ?age[gte]=30
+var ParamOperators = map[string]string{
+	"eq":     EqualConditionOperator,
+	"ne":     NotEqualConditionOperator,
+	"gt":     GreaterConditionOperator,
+	"gte":    GreaterOrEqualConditionOperator,
+	"lt":     LessConditionOperator,
+	"lte":    LessOrEqualConditionOperator,
+	"in":     InConditionOperator,
+	"nin":    NotInConditionOperator,
+	"exists": ExistsConditionOperator,
+}
+
+// QueryParams http query parameters parser example: ?age[gte]=30&status[in]=a,b&sort=-created&page=2&size=50
+type QueryParams struct {
+	fields        map[string]FieldType
+	maxSize       int32
+	ignoreUnknown bool
+	pageParam     string
+	sizeParam     string
+	sortParam     string
+}
+
+// NewQueryParams create http query parameters parser, only fields from whitelist can be filtered and sorted,
+// page size is capped by DefaultSize unless other max size is set (see SetMaxSize)
+func NewQueryParams(fields map[string]FieldType) *QueryParams {
+	return &QueryParams{
+		fields:    fields,
+		maxSize:   DefaultSize,
+		pageParam: DefaultPageParam,
+		sizeParam: DefaultSizeParam,
+		sortParam: DefaultSortParam,
+	}
+}
+
+// SetMaxSize set max page size, bigger sizes are reduced to it, 0 means no cap
+func (q *QueryParams) SetMaxSize(size int32) *QueryParams {
+	q.maxSize = size
+	return q
+}
+
+// SetIgnoreUnknown skip parameters which are not in whitelist instead of error
+func (q *QueryParams) SetIgnoreUnknown(ignore bool) *QueryParams {
+	q.ignoreUnknown = ignore
+	return q
+}
+
+// SetParamNames set names of page, size and sort parameters
+func (q *QueryParams) SetParamNames(page, size, sort string) *QueryParams {
+	q.pageParam, q.sizeParam, q.sortParam = page, size, sort
+	return q
+}
+
+// Links JSON:API style links of page with page and size parameter names of query params (see SetParamNames)
+// example: params.Links(p, "https://api.example.com/users?status=active")
+func (q *QueryParams) Links(p *Pagination, baseURL string) (PaginationLinks, error) {
+	return p.links(baseURL, q.pageParam, q.sizeParam)
+}
+
+// LinkHeader RFC 8288 Link header of page with page and size parameter names of query params (see SetParamNames)
+func (q *QueryParams) LinkHeader(p *Pagination, baseURL string) (string, error) {
+	return p.linkHeader(baseURL, q.pageParam, q.sizeParam)
+}
+
+// Apply apply http query parameters to bom as conditions, sort and limit,
+// all parameters are validated first, so bom is not changed on error
+func (q *QueryParams) Apply(b *Bom, values url.Values) error {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	limit := &Limit{}
+	var sorts []*Sort
+	var filters []Filter
+	for _, key := range keys {
+		for _, value := range values[key] {
+			switch key {
+			case q.pageParam:
+				page, err := q.parseLimit(key, value)
+				if err != nil {
+					return err
+				}
+				limit.Page = page
+			case q.sizeParam:
+				size, err := q.parseLimit(key, value)
+				if err != nil {
+					return err
+				}
+				if q.maxSize > 0 && size > q.maxSize {
+					size = q.maxSize
+				}
+				limit.Size = size
+			case q.sortParam:
+				list, err := q.parseSort(value)
+				if err != nil {
+					return err
+				}
+				sorts = append(sorts, list...)
+			default:
+				filter, err := q.parseCondition(key, value)
+				if err != nil {
+					return err
+				}
+				if filter != nil {
+					filters = append(filters, filter)
+				}
+			}
+		}
+	}
+
+	for _, filter := range filters {
+		b.WhereFilter(filter)
+	}
+	for _, s := range sorts {
+		b.WithSort(s)
+	}
+	b.WithLimit(limit)
+	return nil
+}
+
+// parseSort internal method for parse sort parameter example: sort=-created,name
+func (q *QueryParams) parseSort(value string) ([]*Sort, error) {
+	var sorts []*Sort
+	for _, field := range strings.Split(value, ",") {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+		sortType := "asc"
+		if strings.HasPrefix(field, "-") {
+			field, sortType = field[1:], "desc"
+		} else {
+			field = strings.TrimPrefix(field, "+")
+		}
+		if _, ok := q.fields[field]; !ok {
+			if q.ignoreUnknown {
+				continue
+			}
+			return nil, fmt.Errorf("%w: %s", ErrFieldNotAllowed, field)
+		}
+		sorts = append(sorts, &Sort{Field: field, Type: sortType})
+	}
+	return sorts, nil
+}
+
+// parseCondition internal method for parse condition parameter example: age[gte]=30,
+// nil filter is returned for skipped unknown field
+func (q *QueryParams) parseCondition(key string, value string) (Filter, error) {
+	field, op := key, "eq"
+	if i := strings.Index(key, "["); i > 0 && strings.HasSuffix(key, "]") {
+		field, op = key[:i], key[i+1:len(key)-1]
+	}
+
+	fieldType, ok := q.fields[field]
+	if !ok {
+		if q.ignoreUnknown {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("%w: %s", ErrFieldNotAllowed, field)
+	}
+	operator, ok := ParamOperators[op]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownOperator, op)
+	}
+
+	switch operator {
+	case ExistsConditionOperator:
+		exists, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s: %v", ErrInvalidValue, key, err)
+		}
+		return Exists(field, exists), nil
+	case InConditionOperator, NotInConditionOperator:
+		var list primitive.A
+		for _, item := range strings.Split(value, ",") {
+			v, err := coerceValue(fieldType, strings.TrimSpace(item))
+			if err != nil {
+				return nil, fmt.Errorf("%w: %s: %v", ErrInvalidValue, key, err)
+			}
+			list = append(list, v)
+		}
+		return Condition(field, operator, list), nil
+	}
+	v, err := coerceValue(fieldType, value)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s: %v", ErrInvalidValue, key, err)
+	}
+	return Condition(field, operator, v), nil
+}
+
+// parseLimit internal method for parse page and size parameters
+func (q *QueryParams) parseLimit(key string, value string) (int32, error) {
+	n, err := strconv.ParseInt(value, 10, 32)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("%w: %s: %s", ErrInvalidValue, key, value)
+	}
+	return int32(n), nil
+}
+
+// coerceValue convert string value to field type
+func coerceValue(fieldType FieldType, value string) (interface{}, error) {
+	switch fieldType {
+	case FieldInt:
+		return strconv.ParseInt(value, 10, 64)
+	case FieldFloat:
+		return strconv.ParseFloat(value, 64)
+	case FieldBool:
+		return strconv.ParseBool(value)
+	case FieldTime:
+		return parseTime(value)
+	case FieldObjectID:
+		if _, err := primitive.ObjectIDFromHex(value); err != nil {
+			return nil, err
+		}
+		return ToObj(value), nil
+	}
+	return value, nil
+}
+
+// parseTime parse RFC 3339 time or date
+func parseTime(value string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339Nano, value); err == nil {
+		return t, nil
+	}
+	return time.Parse("2006-01-02", value)
+}
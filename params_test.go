@@ -0,0 +1,128 @@
+package bom
+
+import (
+	"errors"
+	"net/url"
+	"reflect"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestQueryParams_Apply(t *testing.T) {
+	fields := map[string]FieldType{"age": FieldInt, "status": FieldString, "created": FieldTime, "_id": FieldObjectID}
+	tests := []struct {
+		name      string
+		query     string
+		want      primitive.D
+		wantSort  []*Sort
+		wantLimit Limit
+		wantErr   error
+	}{
+		{name: "operators", query: "age[gte]=30&status[in]=a,b",
+			want: primitive.D{{Key: "$and", Value: primitive.A{
+				primitive.D{{Key: "age", Value: primitive.D{{Key: "$gte", Value: int64(30)}}}},
+				primitive.D{{Key: "status", Value: primitive.D{{Key: "$in", Value: primitive.A{"a", "b"}}}}},
+			}}},
+			wantLimit: Limit{Page: 1, Size: DefaultSize}},
+		{name: "object id", query: "_id=5f8f1c3e2a1b3c4d5e6f7a8b",
+			want:      primitive.D{{Key: "_id", Value: ToObj("5f8f1c3e2a1b3c4d5e6f7a8b")}},
+			wantLimit: Limit{Page: 1, Size: DefaultSize}},
+		{name: "sort and limit", query: "sort=-created,age&page=2&size=500",
+			want:      primitive.D{},
+			wantSort:  []*Sort{{Field: "created", Type: "desc"}, {Field: "age", Type: "asc"}},
+			wantLimit: Limit{Page: 2, Size: 100}},
+		{name: "not allowed field", query: "password=1", wantErr: ErrFieldNotAllowed},
+		{name: "invalid value after page and sort", query: "page=2&sort=age&status[exists]=maybe", wantErr: ErrInvalidValue},
+		{name: "not allowed sort", query: "sort=password", wantErr: ErrFieldNotAllowed},
+		{name: "unknown operator", query: "age[regex]=1", wantErr: ErrUnknownOperator},
+		{name: "invalid int", query: "age=abc", wantErr: ErrInvalidValue},
+		{name: "invalid object id", query: "_id=abc", wantErr: ErrInvalidValue},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			values, _ := url.ParseQuery(tt.query)
+			b := &Bom{limit: &Limit{Page: 1, Size: DefaultSize}}
+			err := NewQueryParams(fields).SetMaxSize(100).Apply(b, values)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Apply() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				// bom is not changed on error
+				if len(b.conditions.whereConditions) > 0 || len(b.sort) > 0 || *b.limit != (Limit{Page: 1, Size: DefaultSize}) {
+					t.Errorf("Apply() changed bom on error: %v, %v, %v", b.conditions.whereConditions, b.sort, *b.limit)
+				}
+				return
+			}
+			if got := b.getCondition(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("getCondition() = %v, want %v", got, tt.want)
+			}
+			if !reflect.DeepEqual(b.sort, tt.wantSort) {
+				t.Errorf("sort = %v, want %v", b.sort, tt.wantSort)
+			}
+			if *b.limit != tt.wantLimit {
+				t.Errorf("limit = %v, want %v", *b.limit, tt.wantLimit)
+			}
+		})
+	}
+}
+
+func TestQueryParams_SetMaxSize(t *testing.T) {
+	tests := []struct {
+		name   string
+		params *QueryParams
+		want   int32
+	}{
+		{name: "default cap", params: NewQueryParams(nil), want: DefaultSize},
+		{name: "custom cap", params: NewQueryParams(nil).SetMaxSize(100), want: 100},
+		{name: "no cap", params: NewQueryParams(nil).SetMaxSize(0), want: 500},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := &Bom{limit: &Limit{Page: 1, Size: DefaultSize}}
+			if err := tt.params.Apply(b, url.Values{"size": {"500"}}); err != nil {
+				t.Fatalf("Apply() error = %v", err)
+			}
+			if b.limit.Size != tt.want {
+				t.Errorf("size = %v, want %v", b.limit.Size, tt.want)
+			}
+		})
+	}
+}
+
+func TestQueryParams_Links(t *testing.T) {
+	q := NewQueryParams(map[string]FieldType{"status": FieldString}).SetParamNames("p", "per_page", "order")
+	p := NewPagination(2, 20).WithTotal(45)
+
+	links, err := q.Links(p, "/users?status=active")
+	if err != nil {
+		t.Fatalf("Links() error = %v", err)
+	}
+	want := PaginationLinks{
+		Self:  "/users?p=2&per_page=20&status=active",
+		First: "/users?p=1&per_page=20&status=active",
+		Last:  "/users?p=3&per_page=20&status=active",
+		Prev:  "/users?p=1&per_page=20&status=active",
+		Next:  "/users?p=3&per_page=20&status=active",
+	}
+	if links != want {
+		t.Errorf("Links() = %v, want %v", links, want)
+	}
+
+	header, err := q.LinkHeader(p, "/users")
+	if err != nil {
+		t.Fatalf("LinkHeader() error = %v", err)
+	}
+	wantHeader := `</users?p=1&per_page=20>; rel="first", </users?p=1&per_page=20>; rel="prev", ` +
+		`</users?p=3&per_page=20>; rel="next", </users?p=3&per_page=20>; rel="last"`
+	if header != wantHeader {
+		t.Errorf("LinkHeader() = %v, want %v", header, wantHeader)
+	}
+
+	// links can be parsed back by the same query params
+	u, _ := url.Parse(links.Next)
+	b := &Bom{limit: &Limit{Page: 1, Size: DefaultSize}}
+	if err := q.Apply(b, u.Query()); err != nil || *b.limit != (Limit{Page: 3, Size: 20}) {
+		t.Errorf("Apply() of next link = %v, %v, want page 3 size 20", b.limit, err)
+	}
+}
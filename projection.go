@@ -0,0 +1,117 @@
+package bom
+
+import (
+	"reflect"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsoncodec"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// primitivePkgPath package of bson value types (Timestamp, Binary, Regex, DBPointer and others)
+var primitivePkgPath = reflect.TypeOf(primitive.ObjectID{}).PkgPath()
+
+// Exclude exclude fields from result example: bom.Exclude("password", "history.items")
+func (b *Bom) Exclude(fields ...string) *Bom {
+	for _, field := range fields {
+		b.selectArg = append(b.selectArg, primitive.E{Key: field, Value: 0})
+	}
+	return b
+}
+
+// SelectFor select fields of destination struct by bson tags example: bom.SelectFor(&UserView{}),
+// inline fields are flattened and fields of nested structs are selected by dot path (address.city),
+// bson value types, types with own codec and recursive types are selected as a whole
+func (b *Bom) SelectFor(view interface{}) *Bom {
+	var fields []interface{}
+	for _, field := range projectionFields(reflect.TypeOf(view), "", map[reflect.Type]bool{}) {
+		fields = append(fields, field)
+	}
+	return b.Select(fields...)
+}
+
+// projectionFields internal method for collect dot paths of struct fields by bson tags,
+// visiting holds struct types of current path to stop on recursive types
+func projectionFields(t reflect.Type, prefix string, visiting map[reflect.Type]bool) []string {
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct || visiting[t] {
+		return nil
+	}
+	visiting[t] = true
+	defer delete(visiting, t)
+
+	var fields []string
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.PkgPath != "" && !f.Anonymous {
+			continue
+		}
+		tag, ok := f.Tag.Lookup("bson")
+		if !ok {
+			// the same key is used by bson codec for fields without tag
+			tag = strings.ToLower(f.Name)
+		}
+		parts := strings.Split(tag, ",")
+		name, inline := parts[0], false
+		for _, opt := range parts[1:] {
+			if opt == "inline" {
+				inline = true
+			}
+		}
+		if name == "-" {
+			continue
+		}
+		if inline {
+			fields = append(fields, projectionFields(f.Type, prefix, visiting)...)
+			continue
+		}
+		if f.PkgPath != "" {
+			continue
+		}
+		if name == "" {
+			name = strings.ToLower(f.Name)
+		}
+		if nested := projectionFields(nestedStruct(f.Type), prefix+name+".", visiting); len(nested) > 0 {
+			fields = append(fields, nested...)
+			continue
+		}
+		fields = append(fields, prefix+name)
+	}
+	return fields
+}
+
+// nestedStruct internal method for get struct type which is decoded field by field from embedded document,
+// nil for other types, bson value types and types with own codec or Marshaler (they are selected as a whole)
+func nestedStruct(t reflect.Type) reflect.Type {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct || t.PkgPath() == primitivePkgPath {
+		return nil
+	}
+	// default struct codec is used only for structs without type codec and hooks
+	encoder, err := bson.DefaultRegistry.LookupEncoder(t)
+	if _, ok := encoder.(*bsoncodec.StructCodec); err != nil || !ok {
+		return nil
+	}
+	decoder, err := bson.DefaultRegistry.LookupDecoder(t)
+	if _, ok := decoder.(*bsoncodec.StructCodec); err != nil || !ok {
+		return nil
+	}
+	return t
+}
+
+// removePathCollisions internal method for remove dot paths which are covered by projection of parent
+func removePathCollisions(projection primitive.M) {
+	for key := range projection {
+		for i := strings.LastIndex(key, "."); i > 0; i = strings.LastIndex(key[:i], ".") {
+			if _, ok := projection[key[:i]]; ok {
+				delete(projection, key)
+				break
+			}
+		}
+	}
+}
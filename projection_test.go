@@ -0,0 +1,78 @@
+package bom
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type projectionBase struct {
+	Created time.Time `bson:"created"`
+}
+
+type projectionView struct {
+	projectionBase `bson:",inline"`
+	ID             primitive.ObjectID `bson:"_id"`
+	Name           string             `bson:"name,omitempty"`
+	Secret         string             `bson:"-"`
+	Age            int
+	Address        *struct {
+		City string `bson:"city"`
+	} `bson:"address"`
+	Location Point `bson:"location"`
+	internal string
+}
+
+type projectionNode struct {
+	Name     string            `bson:"name"`
+	Parent   *projectionNode   `bson:"parent"`
+	Children []*projectionNode `bson:"children"`
+	Meta     struct {
+		Owner *projectionNode `bson:"owner"`
+		Rank  int             `bson:"rank"`
+	} `bson:"meta"`
+}
+
+type projectionValues struct {
+	TS      primitive.Timestamp  `bson:"ts"`
+	Data    primitive.Binary     `bson:"b"`
+	Pattern primitive.Regex      `bson:"re"`
+	Ref     *primitive.DBPointer `bson:"ref"`
+	Amount  primitive.Decimal128 `bson:"amount"`
+	Created time.Time            `bson:"created"`
+}
+
+func TestBom_BuildProjection(t *testing.T) {
+	tests := []struct {
+		name  string
+		build func(b *Bom)
+		want  primitive.M
+	}{
+		{name: "exclude", build: func(b *Bom) { b.Exclude("password", "history.items") },
+			want: primitive.M{"password": 0, "history.items": 0}},
+		{name: "dot path covered by parent", build: func(b *Bom) { b.Select("address", "address.city", "name.first") },
+			want: primitive.M{"address": 1, "name.first": 1}},
+		{name: "elem match filter", build: func(b *Bom) {
+			b.Select("name", ElemMatch{Key: "items", Val: Gt("qty", 1)})
+		}, want: primitive.M{"name": 1, "items": primitive.M{ElMathConditionOperator: primitive.D{
+			{Key: "qty", Value: primitive.D{{Key: GreaterConditionOperator, Value: 1}}},
+		}}}},
+		{name: "select for struct", build: func(b *Bom) { b.SelectFor(&projectionView{}) },
+			want: primitive.M{"created": 1, "_id": 1, "name": 1, "age": 1, "address.city": 1, "location": 1}},
+		{name: "select for recursive struct", build: func(b *Bom) { b.SelectFor(projectionNode{}) },
+			want: primitive.M{"name": 1, "parent": 1, "children": 1, "meta.owner": 1, "meta.rank": 1}},
+		{name: "select for bson value types", build: func(b *Bom) { b.SelectFor(&projectionValues{}) },
+			want: primitive.M{"ts": 1, "b": 1, "re": 1, "ref": 1, "amount": 1, "created": 1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := &Bom{}
+			tt.build(b)
+			if got := b.BuildProjection(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("BuildProjection() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,463 @@
+package bom
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// QueryError error of textual query with position (1-based character offset)
+type QueryError struct {
+	Pos int
+	Msg string
+}
+
+// Error error message
+func (e *QueryError) Error() string {
+	return fmt.Sprintf("query error at position %d: %s", e.Pos, e.Msg)
+}
+
+// token kinds of textual query
+const (
+	tokenEOF = iota
+	tokenIdent
+	tokenString
+	tokenLiteral
+	tokenRegex
+	tokenOperator
+	tokenLParen
+	tokenRParen
+	tokenComma
+)
+
+// queryOperators comparison operators of textual query
+var queryOperators = map[string]string{
+	"=":  EqualConditionOperator,
+	"==": EqualConditionOperator,
+	"!=": NotEqualConditionOperator,
+	"<>": NotEqualConditionOperator,
+	">":  GreaterConditionOperator,
+	">=": GreaterOrEqualConditionOperator,
+	"<":  LessConditionOperator,
+	"<=": LessOrEqualConditionOperator,
+	"~":  RegexConditionOperator,
+}
+
+type (
+	// queryToken token of textual query
+	queryToken struct {
+		kind  int
+		text  string
+		value interface{}
+		pos   int
+	}
+
+	// queryParser recursive descent parser of textual query
+	queryParser struct {
+		tokens []queryToken
+		cur    int
+	}
+)
+
+// ParseQuery parse textual query to filter
+// example: bom.ParseQuery(`age >= 30 AND (status IN ("a", "b") OR name ~ "^jo")`)
+//
+// Supported: comparisons (=, !=, >, >=, <, <=, ~ for regex), IN, NOT IN, EXISTS, AND, OR, NOT and parentheses.
+// Values are strings ("a" or 'a'), numbers, true, false, null, regular expressions (/^jo/i),
+// dates (2020-01-02, 2020-01-02T15:04:05Z or ISODate("...")) and object ids (24 hex digits or ObjectId("...")).
+func ParseQuery(query string) (Filter, error) {
+	tokens, err := tokenizeQuery(query)
+	if err != nil {
+		return nil, err
+	}
+	p := &queryParser{tokens: tokens}
+	filter, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if t := p.peek(); t.kind != tokenEOF {
+		return nil, p.errorf(t, "unexpected %q", t.text)
+	}
+	return filter, nil
+}
+
+// parseOr parse expressions joined with OR
+func (p *queryParser) parseOr() (Filter, error) {
+	return p.parseLogical("OR", p.parseAnd, Or)
+}
+
+// parseAnd parse expressions joined with AND
+func (p *queryParser) parseAnd() (Filter, error) {
+	return p.parseLogical("AND", p.parseNot, And)
+}
+
+// parseLogical parse expressions joined with logical keyword
+func (p *queryParser) parseLogical(keyword string, next func() (Filter, error), join func(...Filter) *LogicalFilter) (Filter, error) {
+	filter, err := next()
+	if err != nil {
+		return nil, err
+	}
+	filters := []Filter{filter}
+	for p.isKeyword(p.peek(), keyword) {
+		p.next()
+		if filter, err = next(); err != nil {
+			return nil, err
+		}
+		filters = append(filters, filter)
+	}
+	if len(filters) == 1 {
+		return filters[0], nil
+	}
+	return join(filters...), nil
+}
+
+// parseNot parse negation
+func (p *queryParser) parseNot() (Filter, error) {
+	if p.isKeyword(p.peek(), "NOT") {
+		p.next()
+		filter, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return Not(filter), nil
+	}
+	return p.parsePrimary()
+}
+
+// parsePrimary parse parentheses or condition
+func (p *queryParser) parsePrimary() (Filter, error) {
+	t := p.next()
+	switch {
+	case t.kind == tokenLParen:
+		filter, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect(tokenRParen, ")"); err != nil {
+			return nil, err
+		}
+		return filter, nil
+	case t.kind == tokenIdent && !p.isReserved(t):
+		return p.parseCondition(t.text)
+	case t.kind == tokenEOF:
+		return nil, p.errorf(t, "unexpected end of query, expected condition")
+	}
+	return nil, p.errorf(t, "unexpected %q, expected field name", t.text)
+}
+
+// parseCondition parse condition on field
+func (p *queryParser) parseCondition(field string) (Filter, error) {
+	t := p.next()
+	switch {
+	case t.kind == tokenOperator:
+		operator := queryOperators[t.text]
+		v := p.next()
+		if operator == RegexConditionOperator {
+			switch v.kind {
+			case tokenRegex:
+				return Condition(field, operator, v.value), nil
+			case tokenString:
+				return Regex(field, v.value.(string), ""), nil
+			}
+			return nil, p.errorf(v, "unexpected %q, expected regular expression", v.text)
+		}
+		value, err := p.parseValue(v)
+		if err != nil {
+			return nil, err
+		}
+		return Condition(field, operator, value), nil
+	case p.isKeyword(t, "IN"):
+		return p.parseIn(field, InConditionOperator)
+	case p.isKeyword(t, "EXISTS"):
+		return p.parseExists(field, true)
+	case p.isKeyword(t, "NOT"):
+		n := p.next()
+		if p.isKeyword(n, "IN") {
+			return p.parseIn(field, NotInConditionOperator)
+		}
+		if p.isKeyword(n, "EXISTS") {
+			return p.parseExists(field, false)
+		}
+		return nil, p.errorf(n, "unexpected %q, expected IN or EXISTS", n.text)
+	case t.kind == tokenEOF:
+		return nil, p.errorf(t, "unexpected end of query, expected operator after %q", field)
+	}
+	return nil, p.errorf(t, "unexpected %q, expected operator after %q", t.text, field)
+}
+
+// parseIn parse list of IN values
+func (p *queryParser) parseIn(field string, operator string) (Filter, error) {
+	if err := p.expect(tokenLParen, "("); err != nil {
+		return nil, err
+	}
+	var list primitive.A
+	for {
+		value, err := p.parseValue(p.next())
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, value)
+		t := p.next()
+		if t.kind == tokenRParen {
+			break
+		}
+		if t.kind != tokenComma {
+			return nil, p.errorf(t, "unexpected %q, expected , or )", t.text)
+		}
+	}
+	return Condition(field, operator, list), nil
+}
+
+// parseExists parse EXISTS with optional boolean value
+func (p *queryParser) parseExists(field string, exists bool) (Filter, error) {
+	if t := p.peek(); p.isKeyword(t, "TRUE") || p.isKeyword(t, "FALSE") {
+		p.next()
+		exists = exists == p.isKeyword(t, "TRUE")
+	}
+	return Exists(field, exists), nil
+}
+
+// parseValue parse value with type inference
+func (p *queryParser) parseValue(t queryToken) (interface{}, error) {
+	switch t.kind {
+	case tokenString, tokenLiteral, tokenRegex:
+		return t.value, nil
+	case tokenIdent:
+		switch strings.ToUpper(t.text) {
+		case "TRUE":
+			return true, nil
+		case "FALSE":
+			return false, nil
+		case "NULL":
+			return nil, nil
+		case "OBJECTID", "ISODATE":
+			return p.parseFunction(t)
+		}
+		if id, err := primitive.ObjectIDFromHex(t.text); err == nil {
+			return id, nil
+		}
+		return nil, p.errorf(t, "unexpected %q, expected value (strings must be quoted)", t.text)
+	case tokenEOF:
+		return nil, p.errorf(t, "unexpected end of query, expected value")
+	}
+	return nil, p.errorf(t, "unexpected %q, expected value", t.text)
+}
+
+// parseFunction parse ObjectId("...") and ISODate("...")
+func (p *queryParser) parseFunction(name queryToken) (interface{}, error) {
+	if err := p.expect(tokenLParen, "("); err != nil {
+		return nil, err
+	}
+	arg := p.next()
+	if arg.kind != tokenString {
+		return nil, p.errorf(arg, "unexpected %q, expected string argument of %s", arg.text, name.text)
+	}
+	var value interface{}
+	var err error
+	if strings.ToUpper(name.text) == "OBJECTID" {
+		value, err = primitive.ObjectIDFromHex(arg.value.(string))
+	} else {
+		value, err = parseTime(arg.value.(string))
+	}
+	if err != nil {
+		return nil, p.errorf(arg, "invalid argument of %s: %v", name.text, err)
+	}
+	if err := p.expect(tokenRParen, ")"); err != nil {
+		return nil, err
+	}
+	return value, nil
+}
+
+// peek get current token
+func (p *queryParser) peek() queryToken {
+	return p.tokens[p.cur]
+}
+
+// next get current token and move to next one
+func (p *queryParser) next() queryToken {
+	t := p.tokens[p.cur]
+	if t.kind != tokenEOF {
+		p.cur++
+	}
+	return t
+}
+
+// expect check kind of next token
+func (p *queryParser) expect(kind int, text string) error {
+	if t := p.next(); t.kind != kind {
+		if t.kind == tokenEOF {
+			return p.errorf(t, "unexpected end of query, expected %q", text)
+		}
+		return p.errorf(t, "unexpected %q, expected %q", t.text, text)
+	}
+	return nil
+}
+
+// isKeyword check that token is keyword
+func (p *queryParser) isKeyword(t queryToken, keyword string) bool {
+	return t.kind == tokenIdent && strings.EqualFold(t.text, keyword)
+}
+
+// isReserved check that token is reserved word
+func (p *queryParser) isReserved(t queryToken) bool {
+	for _, keyword := range []string{"AND", "OR", "NOT", "IN", "EXISTS"} {
+		if p.isKeyword(t, keyword) {
+			return true
+		}
+	}
+	return false
+}
+
+// errorf create query error at token position
+func (p *queryParser) errorf(t queryToken, format string, args ...interface{}) error {
+	return &QueryError{Pos: t.pos + 1, Msg: fmt.Sprintf(format, args...)}
+}
+
+// tokenizeQuery split textual query to tokens
+func tokenizeQuery(query string) ([]queryToken, error) {
+	var tokens []queryToken
+	runes := []rune(query)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		start := i
+		switch {
+		case unicode.IsSpace(r):
+			i++
+			continue
+		case r == '(':
+			tokens = append(tokens, queryToken{kind: tokenLParen, text: "(", pos: start})
+			i++
+		case r == ')':
+			tokens = append(tokens, queryToken{kind: tokenRParen, text: ")", pos: start})
+			i++
+		case r == ',':
+			tokens = append(tokens, queryToken{kind: tokenComma, text: ",", pos: start})
+			i++
+		case r == '"' || r == '\'':
+			value, end, err := scanString(runes, i)
+			if err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, queryToken{kind: tokenString, text: string(runes[start:end]), value: value, pos: start})
+			i = end
+		case r == '/':
+			value, end, err := scanRegex(runes, i)
+			if err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, queryToken{kind: tokenRegex, text: string(runes[start:end]), value: value, pos: start})
+			i = end
+		case strings.ContainsRune("=!<>~", r):
+			i++
+			if i < len(runes) && strings.ContainsRune("=>", runes[i]) {
+				if _, ok := queryOperators[string(runes[start:i+1])]; ok {
+					i++
+				}
+			}
+			text := string(runes[start:i])
+			if _, ok := queryOperators[text]; !ok {
+				return nil, &QueryError{Pos: start + 1, Msg: fmt.Sprintf("unknown operator %q", text)}
+			}
+			tokens = append(tokens, queryToken{kind: tokenOperator, text: text, pos: start})
+		case unicode.IsDigit(r) || (r == '-' && i+1 < len(runes) && unicode.IsDigit(runes[i+1])):
+			i++
+			for i < len(runes) && (unicode.IsLetter(runes[i]) || unicode.IsDigit(runes[i]) || strings.ContainsRune(".:+-", runes[i])) {
+				i++
+			}
+			text := string(runes[start:i])
+			value, err := parseLiteral(text)
+			if err != nil {
+				return nil, &QueryError{Pos: start + 1, Msg: fmt.Sprintf("invalid literal %q", text)}
+			}
+			tokens = append(tokens, queryToken{kind: tokenLiteral, text: text, value: value, pos: start})
+		case unicode.IsLetter(r) || r == '_' || r == '$':
+			i++
+			for i < len(runes) && (unicode.IsLetter(runes[i]) || unicode.IsDigit(runes[i]) || strings.ContainsRune("_.$", runes[i])) {
+				i++
+			}
+			tokens = append(tokens, queryToken{kind: tokenIdent, text: string(runes[start:i]), pos: start})
+		default:
+			return nil, &QueryError{Pos: start + 1, Msg: fmt.Sprintf("unexpected character %q", r)}
+		}
+	}
+	return append(tokens, queryToken{kind: tokenEOF, pos: len(runes)}), nil
+}
+
+// scanString scan quoted string with escapes
+func scanString(runes []rune, start int) (string, int, error) {
+	quote := runes[start]
+	var sb strings.Builder
+	for i := start + 1; i < len(runes); i++ {
+		switch runes[i] {
+		case quote:
+			return sb.String(), i + 1, nil
+		case '\\':
+			if i+1 >= len(runes) {
+				break
+			}
+			i++
+			switch runes[i] {
+			case 'n':
+				sb.WriteRune('\n')
+			case 't':
+				sb.WriteRune('\t')
+			case 'r':
+				sb.WriteRune('\r')
+			default:
+				sb.WriteRune(runes[i])
+			}
+		default:
+			sb.WriteRune(runes[i])
+		}
+	}
+	return "", 0, &QueryError{Pos: start + 1, Msg: "unterminated string"}
+}
+
+// scanRegex scan regular expression /pattern/options
+func scanRegex(runes []rune, start int) (primitive.Regex, int, error) {
+	var sb strings.Builder
+	for i := start + 1; i < len(runes); i++ {
+		switch runes[i] {
+		case '/':
+			end := i + 1
+			for end < len(runes) && unicode.IsLetter(runes[end]) {
+				end++
+			}
+			return primitive.Regex{Pattern: sb.String(), Options: string(runes[i+1 : end])}, end, nil
+		case '\\':
+			// escaped slash is a part of pattern, other escapes are kept as is
+			if i+1 < len(runes) && runes[i+1] != '/' {
+				sb.WriteRune(runes[i])
+			}
+			if i+1 < len(runes) {
+				i++
+				sb.WriteRune(runes[i])
+			}
+		default:
+			sb.WriteRune(runes[i])
+		}
+	}
+	return primitive.Regex{}, 0, &QueryError{Pos: start + 1, Msg: "unterminated regular expression"}
+}
+
+// parseLiteral infer type of unquoted literal: object id, integer, float or date
+func parseLiteral(text string) (interface{}, error) {
+	if len(text) == 24 {
+		if id, err := primitive.ObjectIDFromHex(text); err == nil {
+			return id, nil
+		}
+	}
+	if n, err := strconv.ParseInt(text, 10, 64); err == nil {
+		return n, nil
+	}
+	if f, err := strconv.ParseFloat(text, 64); err == nil {
+		return f, nil
+	}
+	if t, err := parseTime(text); err == nil {
+		return t, nil
+	}
+	return nil, fmt.Errorf("invalid literal %q", text)
+}
@@ -0,0 +1,83 @@
+package bom
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestParseQuery(t *testing.T) {
+	id := ToObj("5f8f1c3e2a1b3c4d5e6f7a8b")
+	tests := []struct {
+		name  string
+		query string
+		want  Filter
+	}{
+		{name: "precedence", query: `age >= 30 AND (status IN ("a","b") OR name ~ "^jo")`,
+			want: And(Gte("age", int64(30)), Or(In("status", primitive.A{"a", "b"}), Regex("name", "^jo", "")))},
+		{name: "or binds weaker than and", query: `a = 1 OR b = 2 AND c = 3`,
+			want: Or(Eq("a", int64(1)), And(Eq("b", int64(2)), Eq("c", int64(3))))},
+		{name: "types", query: `price < 9.5 AND active = true AND deleted = null AND created > 2020-01-02`,
+			want: And(Lt("price", 9.5), Eq("active", true), Eq("deleted", nil), Gt("created", time.Date(2020, 1, 2, 0, 0, 0, 0, time.UTC)))},
+		{name: "object ids", query: `_id = 5f8f1c3e2a1b3c4d5e6f7a8b OR owner = ObjectId("5f8f1c3e2a1b3c4d5e6f7a8b")`,
+			want: Or(Eq("_id", id), Eq("owner", id))},
+		{name: "negations", query: `NOT (a != 'x') AND b NOT IN (1, 2) AND c NOT EXISTS AND d EXISTS`,
+			want: And(Not(Ne("a", "x")), Nin("b", primitive.A{int64(1), int64(2)}), Exists("c", false), Exists("d", true))},
+		{name: "regex literal", query: `name ~ /^jo\/n/i`,
+			want: Regex("name", "^jo/n", "i")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseQuery(tt.query)
+			if err != nil {
+				t.Fatalf("ParseQuery() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseQuery() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseQuery_RoundTrip(t *testing.T) {
+	filter := And(Gte("age", int64(30)), Or(In("status", primitive.A{"a", "b"}), Not(Eq("name", "jo"))), Exists("email", true))
+	got, err := ParseQuery(filter.String())
+	if err != nil {
+		t.Fatalf("ParseQuery(%q) error = %v", filter.String(), err)
+	}
+	if !reflect.DeepEqual(got, filter) {
+		t.Errorf("ParseQuery(%q) = %v, want %v", filter.String(), got, filter)
+	}
+}
+
+func TestParseQuery_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		query   string
+		wantPos int
+	}{
+		{name: "empty", query: ``, wantPos: 1},
+		{name: "missing value", query: `age >=`, wantPos: 7},
+		{name: "unquoted string", query: `status = active`, wantPos: 10},
+		{name: "unclosed parenthesis", query: `(a = 1 OR b = 2`, wantPos: 16},
+		{name: "unterminated string", query: `a = "x`, wantPos: 5},
+		{name: "unknown operator", query: `a ! 1`, wantPos: 3},
+		{name: "invalid literal", query: `a = 12abc`, wantPos: 5},
+		{name: "trailing token", query: `a = 1 b`, wantPos: 7},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseQuery(tt.query)
+			var qe *QueryError
+			if !errors.As(err, &qe) {
+				t.Fatalf("ParseQuery() error = %v, want QueryError", err)
+			}
+			if qe.Pos != tt.wantPos {
+				t.Errorf("ParseQuery() error position = %d, want %d (%v)", qe.Pos, tt.wantPos, err)
+			}
+		})
+	}
+}